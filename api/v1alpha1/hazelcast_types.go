@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	"encoding/json"
 	"fmt"
 	"hash/fnv"
 	"strings"
@@ -10,10 +11,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Phase represents the current state of the cluster
-// +kubebuilder:validation:Enum=Running;Failed;Pending
+// +kubebuilder:validation:Enum=Running;Failed;Pending;Scaling;Upgrading
 type Phase string
 
 const (
@@ -23,6 +25,29 @@ const (
 	Failed Phase = "Failed"
 	// Pending phase is the state of starting the cluster when not all the members are started yet
 	Pending Phase = "Pending"
+	// Upgrading phase is the state of restarting the members one by one with a new Hazelcast version
+	Upgrading Phase = "Upgrading"
+	// Scaling phase is the state of removing the members one by one when the cluster size is decreased
+	Scaling Phase = "Scaling"
+)
+
+// Condition types of the Hazelcast resource
+const (
+	// ServicesReady condition is true when the Services of the cluster are created and have their addresses assigned
+	ServicesReady = "ServicesReady"
+	// ConfigApplied condition is true when the Hazelcast configuration is generated from the spec without errors
+	ConfigApplied = "ConfigApplied"
+	// StatefulSetReady condition is true when all the members of the cluster are started and ready
+	StatefulSetReady = "StatefulSetReady"
+	// ClientConnected condition is true when the operator is connected to the cluster
+	ClientConnected = "ClientConnected"
+	// RestoreComplete condition is true when the cluster data is restored from the persistence
+	RestoreComplete = "RestoreComplete"
+	// ClusterSafe condition is true when all the partition replicas are in sync and no migrations are in progress
+	ClusterSafe = "ClusterSafe"
+	// EvictionAllowed condition is false when the PodDisruptionBudget does not allow evicting any member,
+	// so node drains and cluster upgrades are blocked
+	EvictionAllowed = "EvictionAllowed"
 )
 
 // HazelcastSpec defines the desired state of Hazelcast
@@ -85,8 +110,57 @@ type HazelcastSpec struct {
 	// +optional
 	// +kubebuilder:default:={repository: "docker.io/hazelcast/platform-operator-agent", version: "0.1.0"}
 	Agent *AgentConfiguration `json:"agent,omitempty"`
+
+	// TLS configuration for member-to-member and client-to-member communication.
+	// +optional
+	TLS *TLSConfiguration `json:"tls,omitempty"`
+
+	// Security configuration for client authentication and authorization.
+	// +optional
+	Security *SecurityConfiguration `json:"security,omitempty"`
+
+	// Name of the ConfigMap with a custom Hazelcast configuration under the "hazelcast.yaml" key.
+	// It is merged with the configuration generated by the operator, the generated values take precedence.
+	// +optional
+	CustomConfigMapRef string `json:"customConfigMapRef,omitempty"`
+
+	// JVM configuration of the Hazelcast members.
+	// When the heap size is not set, it is derived from the memory limit of the Hazelcast container.
+	// +optional
+	JVM *JVMConfiguration `json:"jvm,omitempty"`
+
+	// Hazelcast system properties, e.g. "hazelcast.partition.count".
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// PodDisruptionBudget configuration of the Hazelcast members.
+	// By default, the number of members that can be evicted at once is the lowest total of the backup and async backup counts
+	// of the maps, but at least one, since the members migrate their partitions on graceful shutdown.
+	// Setting maxUnavailable to 0 blocks node drains and cluster upgrades, which is reported by the EvictionAllowed condition.
+	// +optional
+	// +kubebuilder:default:={enabled: true}
+	PodDisruptionBudget *PodDisruptionBudgetConfiguration `json:"podDisruptionBudget,omitempty"`
+
+	// Places the primary and backup replicas of the partitions on members in different nodes or zones,
+	// and spreads the members evenly across them.
+	// +optional
+	HighAvailabilityMode HighAvailabilityMode `json:"highAvailabilityMode,omitempty"`
+
+	// Prometheus metrics exporter configuration of the Hazelcast members.
+	// +optional
+	Metrics *MetricsConfiguration `json:"metrics,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=NODE;ZONE
+type HighAvailabilityMode string
+
+const (
+	// HighAvailabilityModeNode places the partition replicas on members running on different nodes.
+	HighAvailabilityModeNode HighAvailabilityMode = "NODE"
+	// HighAvailabilityModeZone places the partition replicas on members running in different availability zones.
+	HighAvailabilityModeZone HighAvailabilityMode = "ZONE"
+)
+
 // TODO: We need to figure out how to pass default AgentConfiguration
 
 type AgentConfiguration struct {
@@ -101,6 +175,154 @@ type AgentConfiguration struct {
 	Version string `json:"version,omitempty"`
 }
 
+// TLSConfiguration contains the TLS configuration of the Hazelcast cluster.
+type TLSConfiguration struct {
+	// Name of the secret with the TLS certificate, private key and CA certificate.
+	// The secret must contain "tls.crt", "tls.key" and "ca.crt" keys.
+	// The members are restarted when the certificates are changed.
+	// +kubebuilder:validation:MinLength:=1
+	SecretName string `json:"secretName"`
+}
+
+// SecurityConfiguration contains the client security configuration of the Hazelcast cluster.
+// When it is specified, only the declared clients and the operator itself can connect to the cluster.
+type SecurityConfiguration struct {
+	// Identities of the clients allowed to connect to the cluster.
+	// +optional
+	Clients []ClientIdentity `json:"clients,omitempty"`
+}
+
+// ClientIdentity contains the credentials and permissions of a client.
+type ClientIdentity struct {
+	// Name of the identity. It is used as the principal of the client permissions.
+	// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`
+	Name string `json:"name"`
+
+	// Name of the secret with the credentials of the client.
+	// The secret must contain "username" and "password" keys. Token identities are not supported,
+	// since the members verify the client credentials with a simple username and password realm.
+	// A secret with a "token" key is rejected.
+	// The members are restarted when the credentials are changed.
+	// +kubebuilder:validation:MinLength:=1
+	SecretName string `json:"secretName"`
+
+	// Permissions of the client on maps.
+	// +optional
+	MapPermissions []MapPermission `json:"mapPermissions,omitempty"`
+}
+
+// MapPermission grants actions on the maps matching the name.
+type MapPermission struct {
+	// Name of the map. Wildcards are allowed, e.g. "orders-*".
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Actions allowed on the map.
+	// +kubebuilder:validation:MinItems:=1
+	Actions []MapAction `json:"actions"`
+}
+
+// +kubebuilder:validation:Enum=all;create;destroy;put;read;remove;lock;intercept;index;listen
+type MapAction string
+
+const (
+	MapActionAll       MapAction = "all"
+	MapActionCreate    MapAction = "create"
+	MapActionDestroy   MapAction = "destroy"
+	MapActionPut       MapAction = "put"
+	MapActionRead      MapAction = "read"
+	MapActionRemove    MapAction = "remove"
+	MapActionLock      MapAction = "lock"
+	MapActionIntercept MapAction = "intercept"
+	MapActionIndex     MapAction = "index"
+	MapActionListen    MapAction = "listen"
+)
+
+// JVMConfiguration contains the JVM arguments of the Hazelcast members.
+type JVMConfiguration struct {
+	// Memory settings of the JVM.
+	// +optional
+	Memory *JVMMemoryConfiguration `json:"memory,omitempty"`
+
+	// Garbage collector settings of the JVM.
+	// +optional
+	GC *JVMGCConfiguration `json:"gc,omitempty"`
+
+	// Additional JVM arguments, e.g. "-Dhazelcast.diagnostics.enabled=true".
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
+
+// JVMMemoryConfiguration contains the heap settings of the JVM.
+type JVMMemoryConfiguration struct {
+	// Initial heap size, passed as -Xms.
+	// +optional
+	InitialHeap *resource.Quantity `json:"initialHeap,omitempty"`
+
+	// Maximum heap size, passed as -Xmx.
+	// +optional
+	MaxHeap *resource.Quantity `json:"maxHeap,omitempty"`
+}
+
+// JVMGCConfiguration contains the garbage collector settings of the JVM.
+type JVMGCConfiguration struct {
+	// Garbage collector to use.
+	// +optional
+	Collector GCType `json:"collector,omitempty"`
+
+	// Enables the GC logging.
+	// +optional
+	Logging bool `json:"logging,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Serial;Parallel;G1;ZGC
+type GCType string
+
+const (
+	GCTypeSerial   GCType = "Serial"
+	GCTypeParallel GCType = "Parallel"
+	GCTypeG1       GCType = "G1"
+	GCTypeZGC      GCType = "ZGC"
+)
+
+// PodDisruptionBudgetConfiguration contains the configuration of the PodDisruptionBudget created for the pods.
+type PodDisruptionBudgetConfiguration struct {
+	// Enables the PodDisruptionBudget.
+	Enabled bool `json:"enabled"`
+
+	// Maximum number of pods that can be unavailable during voluntary disruptions, e.g. node drains.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// MetricsConfiguration contains the configuration of the Prometheus metrics exporter.
+type MetricsConfiguration struct {
+	// Port of the Prometheus metrics endpoint.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +kubebuilder:default:=8081
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// ServiceMonitor configuration, it is created only when the Prometheus Operator CRDs are installed.
+	// +optional
+	ServiceMonitor *ServiceMonitorConfiguration `json:"serviceMonitor,omitempty"`
+}
+
+// ServiceMonitorConfiguration contains the configuration of the ServiceMonitor scraping the members.
+type ServiceMonitorConfiguration struct {
+	// Enables the ServiceMonitor.
+	Enabled bool `json:"enabled"`
+
+	// Labels added to the ServiceMonitor, e.g. to match the serviceMonitorSelector of Prometheus.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Interval at which the metrics are scraped, e.g. "30s". The Prometheus default is used if not set.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+}
+
 // RestoreConfiguration contains the configuration for Restore operation
 type RestoreConfiguration struct {
 	// Name of the secret with credentials for cloud providers.
@@ -318,6 +540,31 @@ func (c *ExposeExternallyConfiguration) MemberAccessServiceType() corev1.Service
 	}
 }
 
+// IsEnabled returns true if TLS configuration is specified.
+func (t *TLSConfiguration) IsEnabled() bool {
+	return t != nil && t.SecretName != ""
+}
+
+// IsEnabled returns true if the PodDisruptionBudget is enabled.
+func (p *PodDisruptionBudgetConfiguration) IsEnabled() bool {
+	return p != nil && p.Enabled
+}
+
+// IsEnabled returns true if metrics configuration is specified.
+func (m *MetricsConfiguration) IsEnabled() bool {
+	return m != nil
+}
+
+// IsServiceMonitorEnabled returns true if the ServiceMonitor is enabled.
+func (m *MetricsConfiguration) IsServiceMonitorEnabled() bool {
+	return m.IsEnabled() && m.ServiceMonitor != nil && m.ServiceMonitor.Enabled
+}
+
+// IsEnabled returns true if security configuration is specified.
+func (s *SecurityConfiguration) IsEnabled() bool {
+	return s != nil
+}
+
 // Returns true if ClusterDataRecoveryPolicy is not FullRecoveryOnly
 func (p *HazelcastPersistenceConfiguration) AutoRemoveStaleData() bool {
 	return p.ClusterDataRecoveryPolicy != FullRecovery
@@ -379,6 +626,24 @@ type HazelcastStatus struct {
 	// +optional
 	// +kubebuilder:default:={}
 	Restore *RestoreStatus `json:"restore,omitempty"`
+
+	// Number of Hazelcast members connected to the cluster
+	// +optional
+	ClusterSize int32 `json:"clusterSize"`
+
+	// Label selector of the Hazelcast pods, used by the scale subresource
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// Keys of the custom configuration overridden by the configuration generated by the operator
+	// +optional
+	ConfigConflicts []string `json:"configConflicts,omitempty"`
+
+	// Conditions of the Hazelcast cluster
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type RestoreState string
@@ -464,6 +729,7 @@ type HazelcastClusterStatus struct {
 
 // Hazelcast is the Schema for the hazelcasts API
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.clusterSize,statuspath=.status.clusterSize,selectorpath=.status.selector
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="Current state of the Hazelcast deployment"
 // +kubebuilder:printcolumn:name="Members",type="string",JSONPath=".status.hazelcastClusterStatus.readyMembers",description="Current numbers of ready Hazelcast members"
 // +kubebuilder:printcolumn:name="External-Addresses",type="string",JSONPath=".status.externalAddresses",description="External addresses of the Hazelcast cluster"
@@ -482,6 +748,19 @@ func (h *Hazelcast) DockerImage() string {
 	return fmt.Sprintf("%s:%s", h.Spec.Repository, h.Spec.Version)
 }
 
+// LastSuccessfulSpec returns the spec that was applied successfully the last time, or false if there is none.
+func (h *Hazelcast) LastSuccessfulSpec() (*HazelcastSpec, bool) {
+	s, ok := h.ObjectMeta.Annotations[n.LastSuccessfulSpecAnnotation]
+	if !ok {
+		return nil, false
+	}
+	lastSpec := &HazelcastSpec{}
+	if err := json.Unmarshal([]byte(s), lastSpec); err != nil {
+		return nil, false
+	}
+	return lastSpec, true
+}
+
 //+kubebuilder:object:root=true
 
 // HazelcastList contains a list of Hazelcast
@@ -55,6 +55,12 @@ type ManagementCenterSpec struct {
 	// +optional
 	// +kubebuilder:default:={}
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PodDisruptionBudget configuration of Management Center.
+	// When enabled without maxUnavailable, one Management Center pod can be unavailable during voluntary disruptions.
+	// +optional
+	// +kubebuilder:default:={enabled: false}
+	PodDisruptionBudget *PodDisruptionBudgetConfiguration `json:"podDisruptionBudget,omitempty"`
 }
 
 type HazelcastClusterConfig struct {
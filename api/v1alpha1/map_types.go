@@ -13,10 +13,39 @@ type MapSpec struct {
 
 	// Count of synchronous backups.
 	// It cannot be updated after map config is created successfully.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=6
 	// +kubebuilder:default:=1
 	// +optional
 	BackupCount *int32 `json:"backupCount,omitempty"`
 
+	// Count of asynchronous backups. The total of synchronous and asynchronous backups cannot exceed 6.
+	// It cannot be updated after map config is created successfully.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=6
+	// +kubebuilder:default:=0
+	// +optional
+	AsyncBackupCount int32 `json:"asyncBackupCount"`
+
+	// When enabled, the entries are read from the backup replicas on the local member instead of the primary owners.
+	// It can be updated.
+	// +kubebuilder:default:=false
+	// +optional
+	ReadBackupData bool `json:"readBackupData"`
+
+	// Format in which the entries are stored in memory.
+	// It cannot be updated after map config is created successfully.
+	// NATIVE is not supported, since native memory cannot be enabled on the Hazelcast resource.
+	// +kubebuilder:default:="BINARY"
+	// +optional
+	InMemoryFormat InMemoryFormatType `json:"inMemoryFormat,omitempty"`
+
+	// When enabled, the statistics of the map are collected.
+	// It cannot be updated after map config is created successfully.
+	// +kubebuilder:default:=true
+	// +optional
+	StatisticsEnabled *bool `json:"statisticsEnabled,omitempty"`
+
 	// Maximum time in seconds for each entry to stay in the map.
 	// If it is not 0, entries that are older than this time and not updated for this time are evicted automatically.
 	// It can be updated.
@@ -39,7 +68,7 @@ type MapSpec struct {
 
 	// Indexes to be created for the map data.
 	// You can learn more at https://docs.hazelcast.com/hazelcast/latest/query/indexing-maps.
-	// It cannot be updated after map config is created successfully.
+	// New indexes can be added after map config is created successfully, the existing ones cannot be removed or changed.
 	// +optional
 	Indexes []IndexConfig `json:"indexes,omitempty"`
 
@@ -49,6 +78,68 @@ type MapSpec struct {
 	// +optional
 	PersistenceEnabled bool `json:"persistenceEnabled"`
 
+	// Near Cache configuration of the map, the members keep a local copy of the frequently read entries.
+	// It cannot be updated after map config is created successfully.
+	// +optional
+	NearCache *NearCacheConfig `json:"nearCache,omitempty"`
+
+	// Configuration of the MapStore that loads and stores the map entries from and to an external data store.
+	// The MapStore implementation must be on the classpath of the members.
+	// It cannot be updated after map config is created successfully.
+	// +optional
+	MapStore *MapStoreConfig `json:"mapStore,omitempty"`
+
+	// Entry listeners to be registered on the map by the members.
+	// The listener classes must be on the classpath of the members.
+	// It cannot be updated after map config is created successfully.
+	// +optional
+	EntryListeners []EntryListenerConfig `json:"entryListeners,omitempty"`
+
+	// Event Journal configuration of the map, the mutations of the entries are recorded to be consumed as a stream.
+	// It cannot be updated after map config is created successfully.
+	// +optional
+	EventJournal *EventJournalConfig `json:"eventJournal,omitempty"`
+
+	// Merkle tree configuration of the map, it is used by the WAN synchronization to find the differing entries.
+	// It is available only in Hazelcast Enterprise.
+	// It cannot be updated after map config is created successfully.
+	// +optional
+	MerkleTree *MerkleTreeConfig `json:"merkleTree,omitempty"`
+
+	// WAN replication of the map to another cluster.
+	// It is available only in Hazelcast Enterprise and the referenced WAN replication must be defined in the custom configuration of the Hazelcast resource.
+	// It cannot be updated after map config is created successfully.
+	// +optional
+	WanReplicationRef *WanReplicationRef `json:"wanReplicationRef,omitempty"`
+
+	// Continuous query caches of the map, each keeps the entries matching its predicate up to date on the clients.
+	// It cannot be updated after map config is created successfully.
+	// +optional
+	QueryCaches []QueryCacheConfig `json:"queryCaches,omitempty"`
+
+	// Configuration for detecting the changes made to the map config outside of the operator, e.g. through Management Center.
+	// The drift detection is disabled unless it is configured, since each check reads the map config from every member.
+	// It can be updated.
+	// +optional
+	DriftDetection *DriftDetectionConfig `json:"driftDetection,omitempty"`
+
+	// Interval in seconds at which the statistics of the map are refreshed in the status. 0 disables the statistics.
+	// The statistics are collected only if statisticsEnabled is true, and they are read from the metrics of the members,
+	// which are collected every 5 seconds by default.
+	// It can be updated.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=30
+	// +optional
+	StatsRefreshIntervalSeconds *int32 `json:"statsRefreshIntervalSeconds,omitempty"`
+
+	// Policy applied to the map in the cluster when the Map resource is deleted.
+	// Retain keeps the map and its data in the running cluster, but the map config is no longer persisted.
+	// Delete destroys the map and its data, and removes the map config from the persisted configuration.
+	// It can be updated.
+	// +kubebuilder:default:="Retain"
+	// +optional
+	DeletionPolicy DeletionPolicyType `json:"deletionPolicy,omitempty"`
+
 	// HazelcastResourceName defines the name of the Hazelcast resource.
 	// It cannot be updated after map config is created successfully.
 	// +kubebuilder:validation:MinLength:=1
@@ -72,6 +163,257 @@ type EvictionConfig struct {
 	MaxSizePolicy MaxSizePolicyType `json:"maxSizePolicy,omitempty"`
 }
 
+type NearCacheConfig struct {
+	// Format in which the entries are stored in the Near Cache.
+	// NATIVE is not supported, since native memory cannot be enabled on the Hazelcast resource.
+	// +kubebuilder:default:="BINARY"
+	// +optional
+	InMemoryFormat InMemoryFormatType `json:"inMemoryFormat,omitempty"`
+
+	// When enabled, the cached entries are invalidated when they are changed in the cluster.
+	// +kubebuilder:default:=true
+	// +optional
+	InvalidateOnChange *bool `json:"invalidateOnChange,omitempty"`
+
+	// Maximum time in seconds for each entry to stay in the Near Cache. 0 means infinite.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=0
+	// +optional
+	TimeToLiveSeconds int32 `json:"timeToLiveSeconds"`
+
+	// Maximum time in seconds for each entry to stay idle in the Near Cache. 0 means infinite.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=0
+	// +optional
+	MaxIdleSeconds int32 `json:"maxIdleSeconds"`
+
+	// When enabled, the entries owned by the member are cached as well.
+	// +kubebuilder:default:=false
+	// +optional
+	CacheLocalEntries bool `json:"cacheLocalEntries"`
+
+	// Configuration for removing entries from the Near Cache when it reaches its max size.
+	// +kubebuilder:default:={}
+	// +optional
+	Eviction *NearCacheEviction `json:"eviction,omitempty"`
+
+	// Configuration for storing the keys of the Near Cache on disk to warm it up after a restart.
+	// The preloader is used by the Hazelcast clients which use this Near Cache configuration.
+	// +optional
+	Preloader *NearCachePreloader `json:"preloader,omitempty"`
+}
+
+type NearCacheEviction struct {
+	// Eviction policy to be applied when the Near Cache reaches its max size.
+	// +kubebuilder:default:="LRU"
+	// +optional
+	EvictionPolicy EvictionPolicyType `json:"evictionPolicy,omitempty"`
+
+	// Maximum number of entries in the Near Cache.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=10000
+	// +optional
+	Size int32 `json:"size,omitempty"`
+}
+
+type NearCachePreloader struct {
+	// Enables the Near Cache preloader.
+	Enabled bool `json:"enabled"`
+
+	// Directory in which the keys are stored.
+	// +optional
+	Directory string `json:"directory,omitempty"`
+
+	// Delay in seconds before the keys are stored for the first time.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=600
+	// +optional
+	StoreInitialDelaySeconds int32 `json:"storeInitialDelaySeconds,omitempty"`
+
+	// Interval in seconds at which the keys are stored.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=600
+	// +optional
+	StoreIntervalSeconds int32 `json:"storeIntervalSeconds,omitempty"`
+}
+
+type MapStoreConfig struct {
+	// Fully qualified name of the class implementing the MapStore or MapLoader interface.
+	// +kubebuilder:validation:MinLength:=1
+	ClassName string `json:"className"`
+
+	// Number of seconds to delay the calls to the MapStore. 0 means write-through, otherwise write-behind.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=0
+	// +optional
+	WriteDelaySeconds int32 `json:"writeDelaySeconds"`
+
+	// Number of entries written to the MapStore in a single batch in write-behind mode.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=1
+	// +optional
+	WriteBatchSize int32 `json:"writeBatchSize,omitempty"`
+
+	// When enabled, only the latest update of a key is written to the MapStore in write-behind mode.
+	// +kubebuilder:default:=true
+	// +optional
+	WriteCoalescing *bool `json:"writeCoalescing,omitempty"`
+
+	// Whether the entries are loaded when the map is first touched (LAZY) or when the map is created (EAGER).
+	// +kubebuilder:default:="LAZY"
+	// +optional
+	InitialLoadMode InitialLoadModeType `json:"initialLoadMode,omitempty"`
+
+	// Name of the Secret in the namespace of the Map whose data is passed to the MapStore as properties.
+	// +optional
+	PropertiesSecretName string `json:"propertiesSecretName,omitempty"`
+}
+
+type EntryListenerConfig struct {
+	// Fully qualified name of the class implementing the EntryListener interface.
+	// +kubebuilder:validation:MinLength:=1
+	ClassName string `json:"className"`
+
+	// When enabled, the events contain the values of the entries.
+	// +kubebuilder:default:=true
+	// +optional
+	IncludeValue *bool `json:"includeValue,omitempty"`
+
+	// When enabled, the listener receives only the events of the entries owned by its member.
+	// +kubebuilder:default:=false
+	// +optional
+	Local bool `json:"local"`
+}
+
+type EventJournalConfig struct {
+	// Enables the Event Journal.
+	Enabled bool `json:"enabled"`
+
+	// Number of events kept in the Event Journal, it is shared by all partitions of the map.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=10000
+	// +optional
+	Capacity int32 `json:"capacity,omitempty"`
+
+	// Maximum time in seconds for each event to stay in the Event Journal. 0 means infinite.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=0
+	// +optional
+	TimeToLiveSeconds int32 `json:"timeToLiveSeconds"`
+}
+
+type MerkleTreeConfig struct {
+	// Depth of the Merkle tree, deeper trees find the differences more precisely at the cost of more memory.
+	// +kubebuilder:validation:Minimum:=2
+	// +kubebuilder:validation:Maximum:=27
+	// +kubebuilder:default:=10
+	// +optional
+	Depth int32 `json:"depth,omitempty"`
+}
+
+type WanReplicationRef struct {
+	// Name of the WAN replication defined in the custom configuration of the Hazelcast resource.
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Fully qualified name of the merge policy applied to the replicated entries on the target cluster.
+	// +kubebuilder:default:="com.hazelcast.spi.merge.PassThroughMergePolicy"
+	// +optional
+	MergePolicyClassName string `json:"mergePolicyClassName,omitempty"`
+
+	// When enabled, the entries received through WAN replication are replicated to the other WAN targets of this cluster.
+	// +kubebuilder:default:=true
+	// +optional
+	RepublishingEnabled *bool `json:"republishingEnabled,omitempty"`
+
+	// Fully qualified names of the classes filtering the replicated events.
+	// +optional
+	Filters []string `json:"filters,omitempty"`
+}
+
+type QueryCacheConfig struct {
+	// Name of the query cache, the clients use it to get the query cache of the map.
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Predicate filtering the entries kept in the query cache.
+	Predicate QueryCachePredicate `json:"predicate"`
+
+	// Number of events sent to the query cache in a single batch.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// Maximum number of events buffered on each partition before they are sent to the query cache.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:default:=16
+	// +optional
+	BufferSize int32 `json:"bufferSize,omitempty"`
+
+	// When enabled, only the latest update of a key is sent to the query cache in a batch.
+	// +kubebuilder:default:=false
+	// +optional
+	Coalesce bool `json:"coalesce"`
+
+	// When enabled, the query cache is populated with the matching entries when it is created.
+	// +kubebuilder:default:=true
+	// +optional
+	Populate *bool `json:"populate,omitempty"`
+
+	// Indexes to be created for the query cache data.
+	// +optional
+	Indexes []IndexConfig `json:"indexes,omitempty"`
+}
+
+// QueryCachePredicate is either an SQL predicate or the name of a class implementing the Predicate interface.
+type QueryCachePredicate struct {
+	// SQL predicate, e.g. "active AND age > 30".
+	// +optional
+	SQL string `json:"sql,omitempty"`
+
+	// Fully qualified name of the class implementing the Predicate interface.
+	// +optional
+	ClassName string `json:"className,omitempty"`
+}
+
+type DriftDetectionConfig struct {
+	// Interval in seconds at which the map config of the members is compared with the spec. 0 disables the drift detection.
+	// The map config is compared more often if the statistics of the map are refreshed at a shorter interval.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:default:=60
+	// +optional
+	IntervalSeconds *int32 `json:"intervalSeconds,omitempty"`
+
+	// When enabled, the spec is re-applied to the members whose map config has drifted.
+	// Only the fields that can be updated are re-applied.
+	// +kubebuilder:default:=false
+	// +optional
+	Reapply bool `json:"reapply"`
+}
+
+// +kubebuilder:validation:Enum=Retain;Delete
+type DeletionPolicyType string
+
+const (
+	// The map and its data are kept in the cluster when the Map resource is deleted.
+	DeletionPolicyRetain DeletionPolicyType = "Retain"
+
+	// The map and its data are destroyed when the Map resource is deleted.
+	DeletionPolicyDelete DeletionPolicyType = "Delete"
+)
+
+// +kubebuilder:validation:Enum=LAZY;EAGER
+type InitialLoadModeType string
+
+const (
+	// Entries are loaded when the map is first touched.
+	InitialLoadModeLazy InitialLoadModeType = "LAZY"
+
+	// Entries are loaded when the map is created, the call that creates the map waits for the load to complete.
+	InitialLoadModeEager InitialLoadModeType = "EAGER"
+)
+
 // +kubebuilder:validation:Enum=PER_NODE;PER_PARTITION;USED_HEAP_SIZE;USED_HEAP_PERCENTAGE;FREE_HEAP_SIZE;FREE_HEAP_PERCENTAGE;USED_NATIVE_MEMORY_SIZE;USED_NATIVE_MEMORY_PERCENTAGE;FREE_NATIVE_MEMORY_SIZE;FREE_NATIVE_MEMORY_PERCENTAGE
 type MaxSizePolicyType string
 
@@ -148,6 +490,22 @@ type IndexConfig struct {
 	BitmapIndexOptions *BitmapIndexOptionsConfig `json:"bitMapIndexOptions,omitempty"`
 }
 
+// NATIVE is not accepted since native memory cannot be enabled on the Hazelcast resource.
+// +kubebuilder:validation:Enum=BINARY;OBJECT
+type InMemoryFormatType string
+
+const (
+	// Entries are stored in serialized form.
+	InMemoryFormatBinary InMemoryFormatType = "BINARY"
+
+	// Entries are stored in deserialized form, it is efficient for queries and entry processors.
+	InMemoryFormatObject InMemoryFormatType = "OBJECT"
+
+	// Entries are stored in serialized form off-heap. It is available only in Hazelcast Enterprise HD.
+	// It is rejected by the Map resource, since native memory cannot be enabled on the Hazelcast resource.
+	InMemoryFormatNative InMemoryFormatType = "NATIVE"
+)
+
 // +kubebuilder:validation:Enum=SORTED;HASH;BITMAP
 type IndexType string
 
@@ -174,9 +532,34 @@ const (
 
 // MapStatus defines the observed state of Map
 type MapStatus struct {
-	State          MapConfigState            `json:"state,omitempty"`
-	Message        string                    `json:"message,omitempty"`
+	State   MapConfigState `json:"state,omitempty"`
+	Message string         `json:"message,omitempty"`
+	// State of the map config on each member by member UUID. The config is applied to the members which join the cluster,
+	// e.g. after a full restart, and the members which left it are removed.
 	MemberStatuses map[string]MapConfigState `json:"memberStatuses,omitempty"`
+	// Indexes added to the existing map by the updates. They are not part of the map config registered on the members,
+	// so they are added separately to the members which join the cluster without the map.
+	AddedIndexes []string `json:"addedIndexes,omitempty"`
+	// Fields of the map config that differ from the spec, per member.
+	DriftedFields map[string][]string `json:"driftedFields,omitempty"`
+	// Time of the last comparison of the map config of the members with the spec.
+	LastDriftCheckTime *metav1.Time `json:"lastDriftCheckTime,omitempty"`
+	// Statistics of the map aggregated across the members.
+	Stats *MapStats `json:"stats,omitempty"`
+}
+
+type MapStats struct {
+	// Number of the entries owned by the members.
+	OwnedEntryCount int64 `json:"ownedEntryCount"`
+	// Number of the backup entries held by the members.
+	BackupEntryCount int64 `json:"backupEntryCount"`
+	// Memory cost of the owned entries in bytes.
+	OwnedEntryMemoryCost int64 `json:"ownedEntryMemoryCost"`
+	// Number of reads of the owned entries.
+	Hits int64 `json:"hits"`
+	// Time of the last update of an entry.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
 }
 
 type MapConfigState string
@@ -187,6 +570,10 @@ const (
 	MapPending MapConfigState = "Pending"
 	// Map config is added into all members but waiting for map to be persisten into ConfigMap
 	MapPersisting MapConfigState = "Persisting"
+	// Map resource is deleted and the map is being removed according to the deletion policy
+	MapTerminating MapConfigState = "Terminating"
+	// Map config of some members differs from the spec
+	MapDrifted MapConfigState = "Drifted"
 )
 
 //+kubebuilder:object:root=true
@@ -194,6 +581,8 @@ const (
 
 // Map is the Schema for the maps API
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.state",description="Current state of the Map Config"
+// +kubebuilder:printcolumn:name="Entries",type="integer",JSONPath=".status.stats.ownedEntryCount",description="Number of entries in the map"
+// +kubebuilder:printcolumn:name="Memory",type="integer",JSONPath=".status.stats.ownedEntryMemoryCost",description="Memory cost of the map entries in bytes"
 // +kubebuilder:printcolumn:name="Message",type="string",priority=1,JSONPath=".status.message",description="Message for the current Map Config"
 type Map struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -203,6 +592,117 @@ type Map struct {
 	Status MapStatus `json:"status,omitempty"`
 }
 
+// GetInMemoryFormat returns the in-memory format of the map, BINARY if it is not set.
+func (ms *MapSpec) GetInMemoryFormat() InMemoryFormatType {
+	if ms.InMemoryFormat == "" {
+		return InMemoryFormatBinary
+	}
+	return ms.InMemoryFormat
+}
+
+// IsStatisticsEnabled returns true if the statistics of the map are collected, which is the default.
+func (ms *MapSpec) IsStatisticsEnabled() bool {
+	return ms.StatisticsEnabled == nil || *ms.StatisticsEnabled
+}
+
+// GetStatsRefreshIntervalSeconds returns the refresh interval of the map statistics, 30 seconds if it is not set.
+func (ms *MapSpec) GetStatsRefreshIntervalSeconds() int32 {
+	if ms.StatsRefreshIntervalSeconds == nil {
+		return 30
+	}
+	return *ms.StatsRefreshIntervalSeconds
+}
+
+// GetInMemoryFormat returns the in-memory format of the Near Cache, BINARY if it is not set.
+func (nc *NearCacheConfig) GetInMemoryFormat() InMemoryFormatType {
+	if nc.InMemoryFormat == "" {
+		return InMemoryFormatBinary
+	}
+	return nc.InMemoryFormat
+}
+
+// IsInvalidateOnChange returns true if the cached entries are invalidated on change, which is the default.
+func (nc *NearCacheConfig) IsInvalidateOnChange() bool {
+	return nc.InvalidateOnChange == nil || *nc.InvalidateOnChange
+}
+
+// IsWriteCoalescing returns true if only the latest update of a key is stored, which is the default.
+func (msc *MapStoreConfig) IsWriteCoalescing() bool {
+	return msc.WriteCoalescing == nil || *msc.WriteCoalescing
+}
+
+// GetInitialLoadMode returns the initial load mode of the MapStore, LAZY if it is not set.
+func (msc *MapStoreConfig) GetInitialLoadMode() InitialLoadModeType {
+	if msc.InitialLoadMode == "" {
+		return InitialLoadModeLazy
+	}
+	return msc.InitialLoadMode
+}
+
+// GetWriteBatchSize returns the write batch size of the MapStore, 1 if it is not set.
+func (msc *MapStoreConfig) GetWriteBatchSize() int32 {
+	if msc.WriteBatchSize == 0 {
+		return 1
+	}
+	return msc.WriteBatchSize
+}
+
+// IsIncludeValue returns true if the events contain the values of the entries, which is the default.
+func (elc *EntryListenerConfig) IsIncludeValue() bool {
+	return elc.IncludeValue == nil || *elc.IncludeValue
+}
+
+// GetCapacity returns the capacity of the Event Journal, 10000 if it is not set.
+func (ejc *EventJournalConfig) GetCapacity() int32 {
+	if ejc.Capacity == 0 {
+		return 10000
+	}
+	return ejc.Capacity
+}
+
+// GetDepth returns the depth of the Merkle tree, 10 if it is not set.
+func (mtc *MerkleTreeConfig) GetDepth() int32 {
+	if mtc.Depth == 0 {
+		return 10
+	}
+	return mtc.Depth
+}
+
+// GetMergePolicyClassName returns the merge policy of the WAN replication, PassThroughMergePolicy if it is not set.
+func (w *WanReplicationRef) GetMergePolicyClassName() string {
+	if w.MergePolicyClassName == "" {
+		return "com.hazelcast.spi.merge.PassThroughMergePolicy"
+	}
+	return w.MergePolicyClassName
+}
+
+// IsRepublishingEnabled returns true if the replicated entries are republished, which is the default.
+func (w *WanReplicationRef) IsRepublishingEnabled() bool {
+	return w.RepublishingEnabled == nil || *w.RepublishingEnabled
+}
+
+// IsPopulate returns true if the query cache is populated when it is created, which is the default.
+func (qc *QueryCacheConfig) IsPopulate() bool {
+	return qc.Populate == nil || *qc.Populate
+}
+
+// GetIntervalSeconds returns the interval of the drift detection, 0 if the drift detection is not configured
+// and 60 seconds if the interval is not set.
+func (dd *DriftDetectionConfig) GetIntervalSeconds() int32 {
+	if dd == nil {
+		return 0
+	}
+	if dd.IntervalSeconds == nil {
+		return 60
+	}
+	return *dd.IntervalSeconds
+}
+
+// IsReapply returns true if the spec is re-applied to the drifted members.
+func (dd *DriftDetectionConfig) IsReapply() bool {
+	return dd != nil && dd.Reapply
+}
+
 func (m *Map) MapName() string {
 	if m.Spec.Name != "" {
 		return m.Spec.Name
@@ -244,6 +744,12 @@ var (
 		EvictionPolicyRandom: 3,
 	}
 
+	EncodeInMemoryFormat = map[InMemoryFormatType]int32{
+		InMemoryFormatBinary: 0,
+		InMemoryFormatObject: 1,
+		InMemoryFormatNative: 2,
+	}
+
 	EncodeIndexType = map[IndexType]int32{
 		IndexTypeSorted: 0,
 		IndexTypeHash:   1,
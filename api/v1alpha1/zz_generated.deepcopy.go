@@ -7,7 +7,9 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -40,6 +42,83 @@ func (in *BitmapIndexOptionsConfig) DeepCopy() *BitmapIndexOptionsConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientIdentity) DeepCopyInto(out *ClientIdentity) {
+	*out = *in
+	if in.MapPermissions != nil {
+		in, out := &in.MapPermissions, &out.MapPermissions
+		*out = make([]MapPermission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientIdentity.
+func (in *ClientIdentity) DeepCopy() *ClientIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetectionConfig) DeepCopyInto(out *DriftDetectionConfig) {
+	*out = *in
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetectionConfig.
+func (in *DriftDetectionConfig) DeepCopy() *DriftDetectionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetectionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EntryListenerConfig) DeepCopyInto(out *EntryListenerConfig) {
+	*out = *in
+	if in.IncludeValue != nil {
+		in, out := &in.IncludeValue, &out.IncludeValue
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EntryListenerConfig.
+func (in *EntryListenerConfig) DeepCopy() *EntryListenerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EntryListenerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventJournalConfig) DeepCopyInto(out *EventJournalConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventJournalConfig.
+func (in *EventJournalConfig) DeepCopy() *EventJournalConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EventJournalConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvictionConfig) DeepCopyInto(out *EvictionConfig) {
 	*out = *in
@@ -253,6 +332,38 @@ func (in *HazelcastSpec) DeepCopyInto(out *HazelcastSpec) {
 		*out = new(AgentConfiguration)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfiguration)
+		**out = **in
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecurityConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JVM != nil {
+		in, out := &in.JVM, &out.JVM
+		*out = new(JVMConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HazelcastSpec.
@@ -279,6 +390,18 @@ func (in *HazelcastStatus) DeepCopyInto(out *HazelcastStatus) {
 		*out = new(RestoreStatus)
 		**out = **in
 	}
+	if in.ConfigConflicts != nil {
+		in, out := &in.ConfigConflicts, &out.ConfigConflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HazelcastStatus.
@@ -405,6 +528,76 @@ func (in *IndexConfig) DeepCopy() *IndexConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMConfiguration) DeepCopyInto(out *JVMConfiguration) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(JVMMemoryConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GC != nil {
+		in, out := &in.GC, &out.GC
+		*out = new(JVMGCConfiguration)
+		**out = **in
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMConfiguration.
+func (in *JVMConfiguration) DeepCopy() *JVMConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMGCConfiguration) DeepCopyInto(out *JVMGCConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMGCConfiguration.
+func (in *JVMGCConfiguration) DeepCopy() *JVMGCConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMGCConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMMemoryConfiguration) DeepCopyInto(out *JVMMemoryConfiguration) {
+	*out = *in
+	if in.InitialHeap != nil {
+		in, out := &in.InitialHeap, &out.InitialHeap
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxHeap != nil {
+		in, out := &in.MaxHeap, &out.MaxHeap
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMMemoryConfiguration.
+func (in *JVMMemoryConfiguration) DeepCopy() *JVMMemoryConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMMemoryConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementCenter) DeepCopyInto(out *ManagementCenter) {
 	*out = *in
@@ -497,6 +690,11 @@ func (in *ManagementCenterSpec) DeepCopyInto(out *ManagementCenterSpec) {
 		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudgetConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagementCenterSpec.
@@ -583,6 +781,26 @@ func (in *MapList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MapPermission) DeepCopyInto(out *MapPermission) {
+	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]MapAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapPermission.
+func (in *MapPermission) DeepCopy() *MapPermission {
+	if in == nil {
+		return nil
+	}
+	out := new(MapPermission)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MapSpec) DeepCopyInto(out *MapSpec) {
 	*out = *in
@@ -591,6 +809,11 @@ func (in *MapSpec) DeepCopyInto(out *MapSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.StatisticsEnabled != nil {
+		in, out := &in.StatisticsEnabled, &out.StatisticsEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.TimeToLiveSeconds != nil {
 		in, out := &in.TimeToLiveSeconds, &out.TimeToLiveSeconds
 		*out = new(int32)
@@ -613,6 +836,55 @@ func (in *MapSpec) DeepCopyInto(out *MapSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NearCache != nil {
+		in, out := &in.NearCache, &out.NearCache
+		*out = new(NearCacheConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MapStore != nil {
+		in, out := &in.MapStore, &out.MapStore
+		*out = new(MapStoreConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EntryListeners != nil {
+		in, out := &in.EntryListeners, &out.EntryListeners
+		*out = make([]EntryListenerConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EventJournal != nil {
+		in, out := &in.EventJournal, &out.EventJournal
+		*out = new(EventJournalConfig)
+		**out = **in
+	}
+	if in.MerkleTree != nil {
+		in, out := &in.MerkleTree, &out.MerkleTree
+		*out = new(MerkleTreeConfig)
+		**out = **in
+	}
+	if in.WanReplicationRef != nil {
+		in, out := &in.WanReplicationRef, &out.WanReplicationRef
+		*out = new(WanReplicationRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueryCaches != nil {
+		in, out := &in.QueryCaches, &out.QueryCaches
+		*out = make([]QueryCacheConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatsRefreshIntervalSeconds != nil {
+		in, out := &in.StatsRefreshIntervalSeconds, &out.StatsRefreshIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapSpec.
@@ -625,6 +897,25 @@ func (in *MapSpec) DeepCopy() *MapSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MapStats) DeepCopyInto(out *MapStats) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapStats.
+func (in *MapStats) DeepCopy() *MapStats {
+	if in == nil {
+		return nil
+	}
+	out := new(MapStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MapStatus) DeepCopyInto(out *MapStatus) {
 	*out = *in
@@ -635,6 +926,35 @@ func (in *MapStatus) DeepCopyInto(out *MapStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.AddedIndexes != nil {
+		in, out := &in.AddedIndexes, &out.AddedIndexes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DriftedFields != nil {
+		in, out := &in.DriftedFields, &out.DriftedFields
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.LastDriftCheckTime != nil {
+		in, out := &in.LastDriftCheckTime, &out.LastDriftCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Stats != nil {
+		in, out := &in.Stats, &out.Stats
+		*out = new(MapStats)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapStatus.
@@ -647,6 +967,121 @@ func (in *MapStatus) DeepCopy() *MapStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MapStoreConfig) DeepCopyInto(out *MapStoreConfig) {
+	*out = *in
+	if in.WriteCoalescing != nil {
+		in, out := &in.WriteCoalescing, &out.WriteCoalescing
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapStoreConfig.
+func (in *MapStoreConfig) DeepCopy() *MapStoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MapStoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MerkleTreeConfig) DeepCopyInto(out *MerkleTreeConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MerkleTreeConfig.
+func (in *MerkleTreeConfig) DeepCopy() *MerkleTreeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MerkleTreeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsConfiguration) DeepCopyInto(out *MetricsConfiguration) {
+	*out = *in
+	if in.ServiceMonitor != nil {
+		in, out := &in.ServiceMonitor, &out.ServiceMonitor
+		*out = new(ServiceMonitorConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfiguration.
+func (in *MetricsConfiguration) DeepCopy() *MetricsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NearCacheConfig) DeepCopyInto(out *NearCacheConfig) {
+	*out = *in
+	if in.InvalidateOnChange != nil {
+		in, out := &in.InvalidateOnChange, &out.InvalidateOnChange
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Eviction != nil {
+		in, out := &in.Eviction, &out.Eviction
+		*out = new(NearCacheEviction)
+		**out = **in
+	}
+	if in.Preloader != nil {
+		in, out := &in.Preloader, &out.Preloader
+		*out = new(NearCachePreloader)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NearCacheConfig.
+func (in *NearCacheConfig) DeepCopy() *NearCacheConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NearCacheConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NearCacheEviction) DeepCopyInto(out *NearCacheEviction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NearCacheEviction.
+func (in *NearCacheEviction) DeepCopy() *NearCacheEviction {
+	if in == nil {
+		return nil
+	}
+	out := new(NearCacheEviction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NearCachePreloader) DeepCopyInto(out *NearCachePreloader) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NearCachePreloader.
+func (in *NearCachePreloader) DeepCopy() *NearCachePreloader {
+	if in == nil {
+		return nil
+	}
+	out := new(NearCachePreloader)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistenceConfiguration) DeepCopyInto(out *PersistenceConfiguration) {
 	*out = *in
@@ -702,6 +1137,69 @@ func (in *PersistencePvcConfiguration) DeepCopy() *PersistencePvcConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetConfiguration) DeepCopyInto(out *PodDisruptionBudgetConfiguration) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetConfiguration.
+func (in *PodDisruptionBudgetConfiguration) DeepCopy() *PodDisruptionBudgetConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryCacheConfig) DeepCopyInto(out *QueryCacheConfig) {
+	*out = *in
+	out.Predicate = in.Predicate
+	if in.Populate != nil {
+		in, out := &in.Populate, &out.Populate
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Indexes != nil {
+		in, out := &in.Indexes, &out.Indexes
+		*out = make([]IndexConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryCacheConfig.
+func (in *QueryCacheConfig) DeepCopy() *QueryCacheConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryCacheConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryCachePredicate) DeepCopyInto(out *QueryCachePredicate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryCachePredicate.
+func (in *QueryCachePredicate) DeepCopy() *QueryCachePredicate {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryCachePredicate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestoreConfiguration) DeepCopyInto(out *RestoreConfiguration) {
 	*out = *in
@@ -772,3 +1270,87 @@ func (in *SchedulingConfiguration) DeepCopy() *SchedulingConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityConfiguration) DeepCopyInto(out *SecurityConfiguration) {
+	*out = *in
+	if in.Clients != nil {
+		in, out := &in.Clients, &out.Clients
+		*out = make([]ClientIdentity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityConfiguration.
+func (in *SecurityConfiguration) DeepCopy() *SecurityConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMonitorConfiguration) DeepCopyInto(out *ServiceMonitorConfiguration) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorConfiguration.
+func (in *ServiceMonitorConfiguration) DeepCopy() *ServiceMonitorConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMonitorConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfiguration) DeepCopyInto(out *TLSConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfiguration.
+func (in *TLSConfiguration) DeepCopy() *TLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WanReplicationRef) DeepCopyInto(out *WanReplicationRef) {
+	*out = *in
+	if in.RepublishingEnabled != nil {
+		in, out := &in.RepublishingEnabled, &out.RepublishingEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WanReplicationRef.
+func (in *WanReplicationRef) DeepCopy() *WanReplicationRef {
+	if in == nil {
+		return nil
+	}
+	out := new(WanReplicationRef)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,94 @@
+package hazelcast
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+)
+
+// Reasons of the conditions and events of the Hazelcast resource
+const (
+	reasonReady             = "Ready"
+	reasonFailed            = "Failed"
+	reasonValidationFailed  = "ValidationFailed"
+	reasonServicesNotReady  = "ServicesNotReady"
+	reasonConfigConflicts   = "ConfigConflicts"
+	reasonMembersNotReady   = "MembersNotReady"
+	reasonScaling           = "Scaling"
+	reasonUpgrading         = "Upgrading"
+	reasonConnected         = "Connected"
+	reasonNotConnected      = "NotConnected"
+	reasonRestoreInProgress = "RestoreInProgress"
+	reasonSafe              = "Safe"
+	reasonNotSafe           = "NotSafe"
+	reasonPhaseChanged      = "PhaseChanged"
+	reasonForceStart        = "ForceStart"
+	reasonForceStartFailed  = "ForceStartFailed"
+	reasonChangeState       = "ChangeState"
+	reasonChangeStateFailed = "ChangeStateFailed"
+	reasonCheckFailed       = "CheckFailed"
+	reasonScalingPostponed  = "ScalingPostponed"
+	reasonEvictionBlocked   = "EvictionBlocked"
+)
+
+// setCondition adds or updates the condition of the given type, the last transition time is changed only if the status changes.
+func setCondition(h *hazelcastv1alpha1.Hazelcast, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&h.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: h.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func setConditionTrue(h *hazelcastv1alpha1.Hazelcast, conditionType, reason, message string) {
+	setCondition(h, conditionType, metav1.ConditionTrue, reason, message)
+}
+
+func setConditionFalse(h *hazelcastv1alpha1.Hazelcast, conditionType, reason, message string) {
+	setCondition(h, conditionType, metav1.ConditionFalse, reason, message)
+}
+
+func setConfigAppliedCondition(h *hazelcastv1alpha1.Hazelcast) {
+	if len(h.Status.ConfigConflicts) != 0 {
+		setConditionTrue(h, hazelcastv1alpha1.ConfigApplied, reasonConfigConflicts,
+			fmt.Sprintf("Custom configuration overridden by the operator: %s", strings.Join(h.Status.ConfigConflicts, ", ")))
+		return
+	}
+	setConditionTrue(h, hazelcastv1alpha1.ConfigApplied, reasonReady, "")
+}
+
+// setClusterSafeCondition records the result of the cluster safety check.
+func setClusterSafeCondition(h *hazelcastv1alpha1.Hazelcast, safe bool, err error) {
+	switch {
+	case err != nil:
+		setCondition(h, hazelcastv1alpha1.ClusterSafe, metav1.ConditionUnknown, reasonCheckFailed, err.Error())
+	case safe:
+		setConditionTrue(h, hazelcastv1alpha1.ClusterSafe, reasonSafe, "")
+	default:
+		setConditionFalse(h, hazelcastv1alpha1.ClusterSafe, reasonNotSafe, "Partition replicas are not in sync or migrations are in progress")
+	}
+}
+
+// checkClusterSafe updates the ClusterSafe condition with the current state of the cluster.
+func (r *HazelcastReconciler) checkClusterSafe(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, conn connectionConfig) {
+	safe, err := NewRestClient(h, conn).IsClusterSafe(ctx)
+	setClusterSafeCondition(h, safe, err)
+}
+
+func setRestoreCompleteCondition(h *hazelcastv1alpha1.Hazelcast, state hazelcastv1alpha1.RestoreState) {
+	switch state {
+	case hazelcastv1alpha1.RestoreSucceeded:
+		setConditionTrue(h, hazelcastv1alpha1.RestoreComplete, reasonReady, "")
+	case hazelcastv1alpha1.RestoreFailed:
+		setConditionFalse(h, hazelcastv1alpha1.RestoreComplete, reasonFailed, "Cluster data could not be restored")
+	case hazelcastv1alpha1.RestoreInProgress:
+		setConditionFalse(h, hazelcastv1alpha1.RestoreComplete, reasonRestoreInProgress, "")
+	}
+}
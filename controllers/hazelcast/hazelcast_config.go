@@ -13,7 +13,7 @@ import (
 	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
 )
 
-func buildConfig(h *hazelcastv1alpha1.Hazelcast) hazelcast.Config {
+func buildConfig(h *hazelcastv1alpha1.Hazelcast, conn connectionConfig) hazelcast.Config {
 	config := hazelcast.Config{
 		Logger: logger.Config{
 			Level: logger.OffLevel,
@@ -22,10 +22,21 @@ func buildConfig(h *hazelcastv1alpha1.Hazelcast) hazelcast.Config {
 	cc := &config.Cluster
 	cc.Name = h.Spec.ClusterName
 	cc.Network.SetAddresses(hazelcastUrl(h))
+	if conn.tls != nil {
+		cc.Network.SSL.Enabled = true
+		cc.Network.SSL.SetTLSConfig(conn.tls)
+	}
+	if conn.credentials != nil {
+		cc.Security.Credentials.Username = conn.credentials.username
+		cc.Security.Credentials.Password = conn.credentials.password
+	}
 	return config
 }
 
 func restUrl(h *hazelcastv1alpha1.Hazelcast) string {
+	if h.Spec.TLS.IsEnabled() {
+		return fmt.Sprintf("https://%s", hazelcastUrl(h))
+	}
 	return fmt.Sprintf("http://%s", hazelcastUrl(h))
 }
 
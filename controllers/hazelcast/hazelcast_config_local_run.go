@@ -16,7 +16,7 @@ import (
 
 const localUrl = "127.0.0.1:8000"
 
-func buildConfig(h *hazelcastv1alpha1.Hazelcast) hazelcast.Config {
+func buildConfig(h *hazelcastv1alpha1.Hazelcast, conn connectionConfig) hazelcast.Config {
 	config := hazelcast.Config{
 		Logger: logger.Config{
 			Level: logger.OffLevel,
@@ -25,11 +25,22 @@ func buildConfig(h *hazelcastv1alpha1.Hazelcast) hazelcast.Config {
 	cc := &config.Cluster
 	cc.Name = h.Spec.ClusterName
 	cc.Network.SetAddresses(hazelcastUrl(h))
+	if conn.tls != nil {
+		cc.Network.SSL.Enabled = true
+		cc.Network.SSL.SetTLSConfig(conn.tls)
+	}
+	if conn.credentials != nil {
+		cc.Security.Credentials.Username = conn.credentials.username
+		cc.Security.Credentials.Password = conn.credentials.password
+	}
 	cc.Unisocket = true
 	return config
 }
 
 func restUrl(h *hazelcastv1alpha1.Hazelcast) string {
+	if h.Spec.TLS.IsEnabled() {
+		return fmt.Sprintf("https://%s", hazelcastUrl(h))
+	}
 	return fmt.Sprintf("http://%s", hazelcastUrl(h))
 }
 
@@ -8,10 +8,12 @@ import (
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -34,15 +36,17 @@ type HazelcastReconciler struct {
 	client.Client
 	Log                  logr.Logger
 	Scheme               *runtime.Scheme
+	Recorder             record.EventRecorder
 	triggerReconcileChan chan event.GenericEvent
 	metrics              *phonehome.Metrics
 }
 
-func NewHazelcastReconciler(c client.Client, log logr.Logger, s *runtime.Scheme, m *phonehome.Metrics) *HazelcastReconciler {
+func NewHazelcastReconciler(c client.Client, log logr.Logger, s *runtime.Scheme, rec record.EventRecorder, m *phonehome.Metrics) *HazelcastReconciler {
 	return &HazelcastReconciler{
 		Client:               c,
 		Log:                  log,
 		Scheme:               s,
+		Recorder:             rec,
 		triggerReconcileChan: make(chan event.GenericEvent),
 		metrics:              m,
 	}
@@ -59,8 +63,10 @@ func NewHazelcastReconciler(c client.Client, log logr.Logger, s *runtime.Scheme,
 // ClusterRole inherited from Hazelcast ClusterRole
 //+kubebuilder:rbac:groups="",resources=endpoints;secrets;pods;nodes;services,verbs=get;list
 // Role related to Reconcile()
-//+kubebuilder:rbac:groups="",resources=events;services;serviceaccounts;configmaps;pods,verbs=get;list;watch;create;update;patch;delete,namespace=system
+//+kubebuilder:rbac:groups="",resources=events;services;serviceaccounts;configmaps;pods;secrets,verbs=get;list;watch;create;update;patch;delete,namespace=system
 //+kubebuilder:rbac:groups="apps",resources=statefulsets,verbs=get;list;watch;create;update;patch;delete,namespace=system
+//+kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete,namespace=system
+//+kubebuilder:rbac:groups="monitoring.coreos.com",resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete,namespace=system
 // ClusterRole related to Reconcile()
 //+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
 
@@ -74,13 +80,13 @@ func (r *HazelcastReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			logger.Info("Hazelcast resource not found. Ignoring since object must be deleted")
 			return ctrl.Result{}, nil
 		}
-		return update(ctx, r.Client, h, failedPhase(err))
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	// Add finalizer for Hazelcast CR to cleanup ClusterRole
 	err = r.addFinalizer(ctx, h, logger)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	// Check if the Hazelcast CR is marked to be deleted
@@ -88,7 +94,7 @@ func (r *HazelcastReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		// Execute finalizer's pre-delete function to cleanup ClusterRole
 		err = r.executeFinalizer(ctx, h, logger)
 		if err != nil {
-			return update(ctx, r.Client, h, failedPhase(err))
+			return r.update(ctx, h, failedPhase(err))
 		}
 		logger.V(2).Info("Finalizer's pre-delete function executed successfully and the finalizer removed from custom resource", "Name:", n.Finalizer)
 		return ctrl.Result{}, nil
@@ -103,79 +109,139 @@ func (r *HazelcastReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 	err = validation.ValidateSpec(h)
 	if err != nil {
-		return update(ctx, r.Client, h,
+		setConditionFalse(h, hazelcastv1alpha1.ConfigApplied, reasonValidationFailed, err.Error())
+		return r.update(ctx, h,
 			failedPhase(err).
 				withMessage(fmt.Sprintf("error validating new Spec: %s", err)))
 	}
 
 	err = r.reconcileClusterRole(ctx, h, logger)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	err = r.reconcileServiceAccount(ctx, h, logger)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	err = r.reconcileClusterRoleBinding(ctx, h, logger)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		return r.update(ctx, h, failedPhase(err))
+	}
+
+	err = r.reconcileOperatorIdentity(ctx, h, logger)
+	if err != nil {
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	err = r.reconcileService(ctx, h, logger)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		setConditionFalse(h, hazelcastv1alpha1.ServicesReady, reasonFailed, err.Error())
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	err = r.reconcileServicePerPod(ctx, h, logger)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		setConditionFalse(h, hazelcastv1alpha1.ServicesReady, reasonFailed, err.Error())
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	err = r.reconcileUnusedServicePerPod(ctx, h)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		setConditionFalse(h, hazelcastv1alpha1.ServicesReady, reasonFailed, err.Error())
+		return r.update(ctx, h, failedPhase(err))
+	}
+
+	err = r.reconcileServiceMonitor(ctx, h, logger)
+	if err != nil {
+		return r.update(ctx, h, failedPhase(err))
 	}
 
 	if !r.isServicePerPodReady(ctx, h) {
 		logger.Info("Service per pod is not ready, waiting.")
-		return update(ctx, r.Client, h, pendingPhase(retryAfter))
+		setConditionFalse(h, hazelcastv1alpha1.ServicesReady, reasonServicesNotReady, "Waiting for the external addresses of the Services per pod")
+		return r.update(ctx, h, pendingPhase(retryAfter))
 	}
+	setConditionTrue(h, hazelcastv1alpha1.ServicesReady, reasonReady, "")
 
 	err = r.reconcileConfigMap(ctx, h, logger)
 	if err != nil {
-		return update(ctx, r.Client, h, failedPhase(err))
+		setConditionFalse(h, hazelcastv1alpha1.ConfigApplied, reasonFailed, err.Error())
+		return r.update(ctx, h, failedPhase(err))
 	}
+	setConfigAppliedCondition(h)
 
-	if err = r.reconcileStatefulset(ctx, h, logger); err != nil {
+	replicas, err := r.replicas(ctx, h, logger)
+	if err != nil {
+		return r.update(ctx, h, failedPhase(err))
+	}
+
+	partition, upgrading, err := r.updatePartition(ctx, h, replicas, logger)
+	if err != nil {
+		return r.update(ctx, h, failedPhase(err))
+	}
+
+	if err = r.reconcileStatefulset(ctx, h, replicas, partition, logger); err != nil {
 		// Conflicts are expected and will be handled on the next reconcile loop, no need to error out here
 		if errors.IsConflict(err) {
 			return ctrl.Result{}, nil
 		} else {
-			return update(ctx, r.Client, h, failedPhase(err))
+			setConditionFalse(h, hazelcastv1alpha1.StatefulSetReady, reasonFailed, err.Error())
+			return r.update(ctx, h, failedPhase(err))
 		}
 	}
 
+	err = r.reconcilePodDisruptionBudget(ctx, h, logger)
+	if err != nil {
+		return r.update(ctx, h, failedPhase(err))
+	}
+
+	if replicas != *h.Spec.ClusterSize {
+		setConditionFalse(h, hazelcastv1alpha1.StatefulSetReady, reasonScaling, scalingMessage(h, replicas))
+		return r.update(ctx, h, scalingPhase(scalingRetryAfter(h, time.Now())).
+			withMessage(scalingMessage(h, replicas)))
+	}
+
+	if upgrading {
+		setConditionFalse(h, hazelcastv1alpha1.StatefulSetReady, reasonUpgrading, upgradeMessage(h, partition))
+		return r.update(ctx, h, r.phaseWithStatus(req, upgradingPhase(retryAfter)).
+			withMessage(upgradeMessage(h, partition)))
+	}
+
 	if err = r.checkHotRestart(ctx, h, logger); err != nil {
 		logger.Error(err, "Cluster HotRestart did not finish successfully")
-		return update(ctx, r.Client, h, pendingPhase(retryAfter))
+		return r.update(ctx, h, pendingPhase(retryAfter))
 	}
 
 	if err = r.ensureClusterActive(ctx, h, logger); err != nil {
 		logger.Error(err, "Cluster activation attempt after hot restore failed")
-		return update(ctx, r.Client, h, pendingPhase(retryAfter))
+		return r.update(ctx, h, pendingPhase(retryAfter))
 	}
 
 	if ok, err := util.CheckIfRunning(ctx, r.Client, req.NamespacedName, *h.Spec.ClusterSize); !ok {
 		if err == nil {
-			return update(ctx, r.Client, h, pendingPhase(retryAfter))
+			setConditionFalse(h, hazelcastv1alpha1.StatefulSetReady, reasonMembersNotReady, "Waiting for the members to be ready")
+			return r.update(ctx, h, pendingPhase(retryAfter))
 		} else {
-			return update(ctx, r.Client, h, failedPhase(err).withMessage(err.Error()))
+			setConditionFalse(h, hazelcastv1alpha1.StatefulSetReady, reasonFailed, err.Error())
+			return r.update(ctx, h, failedPhase(err).withMessage(err.Error()))
 		}
 	}
+	setConditionTrue(h, hazelcastv1alpha1.StatefulSetReady, reasonReady, "")
 
-	CreateClient(ctx, h, r.triggerReconcileChan, r.Log)
+	conn, err := newConnectionConfig(ctx, r.Client, h)
+	if err != nil {
+		return r.update(ctx, h, failedPhase(err))
+	}
+	CreateClient(ctx, h, conn, r.triggerReconcileChan, r.Log)
+
+	if err = r.ensureClusterVersion(ctx, h, logger); err != nil {
+		logger.Error(err, "Cluster version upgrade failed")
+		return r.update(ctx, h, pendingPhase(retryAfter).withMessage(err.Error()))
+	}
+
+	r.checkClusterSafe(ctx, h, conn)
 
 	if util.IsPhoneHomeEnabled() {
 		firstDeployment := r.metrics.HazelcastMetrics[h.UID].FillAfterDeployment(h)
@@ -190,16 +256,20 @@ func (r *HazelcastReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	externalAddrs := util.GetExternalAddresses(ctx, r.Client, h, logger)
-	return update(ctx, r.Client, h, r.runningPhaseWithStatus(req).
+	return r.update(ctx, h, r.runningPhaseWithStatus(req).
 		withExternalAddresses(externalAddrs).
 		withMessage(clientConnectionMessage(req)))
 }
 
 func (r *HazelcastReconciler) runningPhaseWithStatus(req ctrl.Request) optionsBuilder {
+	return r.phaseWithStatus(req, runningPhase())
+}
+
+func (r *HazelcastReconciler) phaseWithStatus(req ctrl.Request, o optionsBuilder) optionsBuilder {
 	if hzClient, ok := GetClient(req.NamespacedName); ok {
-		return runningPhase().withStatus(hzClient.Status)
+		return o.withStatus(hzClient.Status)
 	}
-	return runningPhase()
+	return o
 }
 
 func (r *HazelcastReconciler) podUpdates(pod client.Object) []reconcile.Request {
@@ -244,6 +314,50 @@ func (r *HazelcastReconciler) mapUpdates(m client.Object) []reconcile.Request {
 	}
 }
 
+func (r *HazelcastReconciler) customConfigMapUpdates(cm client.Object) []reconcile.Request {
+	hl := &hazelcastv1alpha1.HazelcastList{}
+	err := r.Client.List(context.Background(), hl,
+		client.InNamespace(cm.GetNamespace()),
+		client.MatchingFields{"customConfigMapRef": cm.GetName()})
+	if err != nil {
+		r.Log.Error(err, "Could not list Hazelcast resources referencing the ConfigMap", "ConfigMap", cm.GetName())
+		return []reconcile.Request{}
+	}
+
+	reqs := make([]reconcile.Request, 0, len(hl.Items))
+	for _, h := range hl.Items {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      h.Name,
+				Namespace: h.Namespace,
+			},
+		})
+	}
+	return reqs
+}
+
+func (r *HazelcastReconciler) secretUpdates(s client.Object) []reconcile.Request {
+	hl := &hazelcastv1alpha1.HazelcastList{}
+	err := r.Client.List(context.Background(), hl,
+		client.InNamespace(s.GetNamespace()),
+		client.MatchingFields{"secretRefs": s.GetName()})
+	if err != nil {
+		r.Log.Error(err, "Could not list Hazelcast resources referencing the Secret", "Secret", s.GetName())
+		return []reconcile.Request{}
+	}
+
+	reqs := make([]reconcile.Request, 0, len(hl.Items))
+	for _, h := range hl.Items {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      h.Name,
+				Namespace: h.Namespace,
+			},
+		})
+	}
+	return reqs
+}
+
 func getHazelcastCRName(pod *corev1.Pod) (string, bool) {
 	if pod.Labels[n.ApplicationManagedByLabel] == n.OperatorName && pod.Labels[n.ApplicationNameLabel] == n.Hazelcast {
 		return pod.Labels[n.ApplicationInstanceNameLabel], true
@@ -281,15 +395,31 @@ func (r *HazelcastReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}); err != nil {
 		return err
 	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &hazelcastv1alpha1.Hazelcast{}, "customConfigMapRef", func(rawObj client.Object) []string {
+		h := rawObj.(*hazelcastv1alpha1.Hazelcast)
+		return []string{h.Spec.CustomConfigMapRef}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &hazelcastv1alpha1.Hazelcast{}, "secretRefs", func(rawObj client.Object) []string {
+		h := rawObj.(*hazelcastv1alpha1.Hazelcast)
+		return secretRefs(h)
+	}); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&hazelcastv1alpha1.Hazelcast{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.Secret{}).
+		Owns(&policyv1beta1.PodDisruptionBudget{}).
 		Owns(&rbacv1.ClusterRole{}).
 		Owns(&rbacv1.ClusterRoleBinding{}).
 		Watches(&source.Channel{Source: r.triggerReconcileChan}, &handler.EnqueueRequestForObject{}).
 		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.podUpdates)).
 		Watches(&source.Kind{Type: &hazelcastv1alpha1.Map{}}, handler.EnqueueRequestsFromMapFunc(r.mapUpdates)).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.customConfigMapUpdates)).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(r.secretUpdates)).
 		Complete(r)
 }
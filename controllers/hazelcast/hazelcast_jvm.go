@@ -0,0 +1,46 @@
+package hazelcast
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
+)
+
+var gcFlags = map[hazelcastv1alpha1.GCType]string{
+	hazelcastv1alpha1.GCTypeSerial:   "-XX:+UseSerialGC",
+	hazelcastv1alpha1.GCTypeParallel: "-XX:+UseParallelGC",
+	hazelcastv1alpha1.GCTypeG1:       "-XX:+UseG1GC",
+	hazelcastv1alpha1.GCTypeZGC:      "-XX:+UseZGC",
+}
+
+// jvmArgs returns the JVM arguments of the Hazelcast container.
+func jvmArgs(h *hazelcastv1alpha1.Hazelcast) []string {
+	var args []string
+	jvm := h.Spec.JVM
+	if jvm != nil && jvm.Memory != nil && jvm.Memory.InitialHeap != nil {
+		args = append(args, fmt.Sprintf("-Xms%dm", toMebibytes(jvm.Memory.InitialHeap)))
+	}
+	if maxHeap := util.MaxHeap(h); maxHeap != nil {
+		args = append(args, fmt.Sprintf("-Xmx%dm", toMebibytes(maxHeap)))
+	}
+	if jvm == nil {
+		return args
+	}
+	if jvm.GC != nil {
+		if f, ok := gcFlags[jvm.GC.Collector]; ok {
+			args = append(args, f)
+		}
+		if jvm.GC.Logging {
+			args = append(args, "-Xlog:gc")
+		}
+	}
+	return append(args, jvm.Args...)
+}
+
+// toMebibytes rounds the quantity down to mebibytes, validation ensures that the heap sizes are at least 1Mi.
+func toMebibytes(q *resource.Quantity) int64 {
+	return q.Value() / (1024 * 1024)
+}
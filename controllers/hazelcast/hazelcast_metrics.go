@@ -0,0 +1,144 @@
+package hazelcast
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
+)
+
+const (
+	// jmxExporterAgent is the JMX Prometheus exporter shipped with the Hazelcast images.
+	jmxExporterAgent = "/opt/hazelcast/lib/jmx_prometheus_javaagent.jar"
+	// jmxExporterConfig is the exporter configuration shipped with the Hazelcast images.
+	jmxExporterConfig = "/opt/hazelcast/config/jmx_agent_config.yaml"
+)
+
+var serviceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+func metricsPort(h *hazelcastv1alpha1.Hazelcast) int32 {
+	if h.Spec.Metrics.Port == 0 {
+		return n.DefaultMetricsPort
+	}
+	return h.Spec.Metrics.Port
+}
+
+// metricsJavaOpts returns the JVM options enabling the JMX beans of Hazelcast and exporting them in the Prometheus format.
+func metricsJavaOpts(h *hazelcastv1alpha1.Hazelcast) []string {
+	if !h.Spec.Metrics.IsEnabled() {
+		return nil
+	}
+	return []string{
+		"-Dhazelcast.jmx=true",
+		fmt.Sprintf("-javaagent:%s=%d:%s", jmxExporterAgent, metricsPort(h), jmxExporterConfig),
+	}
+}
+
+func containerPorts(h *hazelcastv1alpha1.Hazelcast) []corev1.ContainerPort {
+	ports := []corev1.ContainerPort{{
+		ContainerPort: n.DefaultHzPort,
+		Name:          n.Hazelcast,
+		Protocol:      corev1.ProtocolTCP,
+	}}
+	if h.Spec.Metrics.IsEnabled() {
+		ports = append(ports, corev1.ContainerPort{
+			ContainerPort: metricsPort(h),
+			Name:          n.MetricsPortName,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
+func metricsServicePort(h *hazelcastv1alpha1.Hazelcast) corev1.ServicePort {
+	return corev1.ServicePort{
+		Name:       n.MetricsPortName,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       metricsPort(h),
+		TargetPort: intstr.FromString(n.MetricsPortName),
+	}
+}
+
+// reconcileServiceMonitor creates the ServiceMonitor scraping the metrics of the members.
+// It is skipped when the Prometheus Operator CRDs are not installed in the cluster.
+func (r *HazelcastReconciler) reconcileServiceMonitor(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) error {
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(serviceMonitorGVK)
+	sm.SetName(h.Name)
+	sm.SetNamespace(h.Namespace)
+
+	if !h.Spec.Metrics.IsServiceMonitorEnabled() {
+		err := r.Client.Delete(ctx, sm)
+		if err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete ServiceMonitor: %w", err)
+		}
+		return nil
+	}
+
+	err := controllerutil.SetControllerReference(h, sm, r.Scheme)
+	if err != nil {
+		return fmt.Errorf("failed to set owner reference on ServiceMonitor: %w", err)
+	}
+
+	opResult, err := util.CreateOrUpdate(ctx, r.Client, sm, func() error {
+		ls := labels(h)
+		for k, v := range h.Spec.Metrics.ServiceMonitor.Labels {
+			ls[k] = v
+		}
+		sm.SetLabels(ls)
+		sm.Object["spec"] = serviceMonitorSpec(h)
+		return nil
+	})
+	if meta.IsNoMatchError(err) {
+		logger.Info("ServiceMonitor CRD is not installed, skipping the ServiceMonitor creation")
+		return nil
+	}
+	if opResult != controllerutil.OperationResultNone {
+		logger.Info("Operation result", "ServiceMonitor", h.Name, "result", opResult)
+	}
+	return err
+}
+
+func serviceMonitorSpec(h *hazelcastv1alpha1.Hazelcast) map[string]interface{} {
+	matchLabels := map[string]interface{}{}
+	for k, v := range labels(h) {
+		matchLabels[k] = v
+	}
+	endpoint := map[string]interface{}{
+		"port": n.MetricsPortName,
+	}
+	if h.Spec.Metrics.ServiceMonitor.Interval != "" {
+		endpoint["interval"] = h.Spec.Metrics.ServiceMonitor.Interval
+	}
+	return map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": matchLabels,
+			// The members are scraped once through the cluster Service, not through the Services per pod
+			"matchExpressions": []interface{}{
+				map[string]interface{}{
+					"key":      n.ServicePerPodLabelName,
+					"operator": "DoesNotExist",
+				},
+			},
+		},
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{h.Namespace},
+		},
+		"endpoints": []interface{}{endpoint},
+	}
+}
@@ -6,16 +6,21 @@ import (
 	"fmt"
 	"github.com/hazelcast/hazelcast-platform-operator/controllers/hazelcast/validation"
 	"hash/crc32"
+	"math"
 	"net"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"gopkg.in/yaml.v3"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	errors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -24,6 +29,7 @@ import (
 
 	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/config"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/metrics"
 	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/platform"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
@@ -66,6 +72,7 @@ func (r *HazelcastReconciler) executeFinalizer(ctx context.Context, h *hazelcast
 	if util.IsPhoneHomeEnabled() {
 		delete(r.metrics.HazelcastMetrics, h.UID)
 	}
+	metrics.DeleteClusterStatus(h)
 	ShutdownClient(ctx, types.NamespacedName{Name: h.Name, Namespace: h.Namespace})
 	return nil
 }
@@ -187,23 +194,12 @@ func (r *HazelcastReconciler) reconcileClusterRoleBinding(ctx context.Context, h
 }
 
 func (r *HazelcastReconciler) reconcileService(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) error {
-	var service *corev1.Service
-	if h.Spec.Persistence.IsExternal() {
-		service = &corev1.Service{
-			ObjectMeta: metadata(h),
-			Spec: corev1.ServiceSpec{
-				Selector: labels(h),
-				Ports:    hazelcastAndAgentPort(),
-			},
-		}
-	} else {
-		service = &corev1.Service{
-			ObjectMeta: metadata(h),
-			Spec: corev1.ServiceSpec{
-				Selector: labels(h),
-				Ports:    hazelcastPort(),
-			},
-		}
+	service := &corev1.Service{
+		ObjectMeta: metadata(h),
+		Spec: corev1.ServiceSpec{
+			Selector: labels(h),
+			Ports:    servicePorts(h),
+		},
 	}
 
 	err := controllerutil.SetControllerReference(h, service, r.Scheme)
@@ -213,9 +209,12 @@ func (r *HazelcastReconciler) reconcileService(ctx context.Context, h *hazelcast
 
 	opResult, err := util.CreateOrUpdate(ctx, r.Client, service, func() error {
 		service.Spec.Type = serviceType(h)
+		service.Spec.Ports = updatedPorts(service.Spec.Ports, servicePorts(h))
 		if serviceType(h) == corev1.ServiceTypeClusterIP {
 			// dirty hack to prevent the error when changing the service type
-			service.Spec.Ports[0].NodePort = 0
+			for i := range service.Spec.Ports {
+				service.Spec.Ports[i].NodePort = 0
+			}
 		}
 
 		return nil
@@ -248,7 +247,7 @@ func (r *HazelcastReconciler) reconcileServicePerPod(ctx context.Context, h *haz
 			},
 			Spec: corev1.ServiceSpec{
 				Selector:                 servicePerPodSelector(i, h),
-				Ports:                    hazelcastPort(),
+				Ports:                    hazelcastPort(h),
 				PublishNotReadyAddresses: true,
 			},
 		}
@@ -260,6 +259,7 @@ func (r *HazelcastReconciler) reconcileServicePerPod(ctx context.Context, h *haz
 
 		opResult, err := util.CreateOrUpdate(ctx, r.Client, service, func() error {
 			service.Spec.Type = h.Spec.ExposeExternally.MemberAccessServiceType()
+			service.Spec.Ports = updatedPorts(service.Spec.Ports, hazelcastPort(h))
 			return nil
 		})
 
@@ -336,32 +336,49 @@ func servicePerPodLabels(h *hazelcastv1alpha1.Hazelcast) map[string]string {
 	return ls
 }
 
-func hazelcastPort() []v1.ServicePort {
-	return []corev1.ServicePort{
-		{
-			Name:       n.HazelcastPortName,
-			Protocol:   corev1.ProtocolTCP,
-			Port:       n.DefaultHzPort,
-			TargetPort: intstr.FromString(n.Hazelcast),
-		},
+func servicePorts(h *hazelcastv1alpha1.Hazelcast) []v1.ServicePort {
+	if h.Spec.Persistence.IsExternal() {
+		return hazelcastAndAgentPort(h)
 	}
+	return hazelcastPort(h)
 }
 
-func hazelcastAndAgentPort() []v1.ServicePort {
-	return []corev1.ServicePort{
+func hazelcastPort(h *hazelcastv1alpha1.Hazelcast) []v1.ServicePort {
+	ports := []corev1.ServicePort{
 		{
 			Name:       n.HazelcastPortName,
 			Protocol:   corev1.ProtocolTCP,
 			Port:       n.DefaultHzPort,
 			TargetPort: intstr.FromString(n.Hazelcast),
 		},
-		{
-			Name:       n.BackupAgentPortName,
-			Protocol:   corev1.ProtocolTCP,
-			Port:       n.DefaultAgentPort,
-			TargetPort: intstr.FromString(n.BackupAgent),
-		},
 	}
+	if h.Spec.Metrics.IsEnabled() {
+		ports = append(ports, metricsServicePort(h))
+	}
+	return ports
+}
+
+func hazelcastAndAgentPort(h *hazelcastv1alpha1.Hazelcast) []v1.ServicePort {
+	return append(hazelcastPort(h), corev1.ServicePort{
+		Name:       n.BackupAgentPortName,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       n.DefaultAgentPort,
+		TargetPort: intstr.FromString(n.BackupAgent),
+	})
+}
+
+// updatedPorts returns the desired ports of a Service, keeping the node ports already allocated to the existing ones.
+func updatedPorts(existing, desired []v1.ServicePort) []v1.ServicePort {
+	nodePorts := make(map[string]int32, len(existing))
+	for _, p := range existing {
+		nodePorts[p.Name] = p.NodePort
+	}
+	ports := make([]v1.ServicePort, 0, len(desired))
+	for _, p := range desired {
+		p.NodePort = nodePorts[p.Name]
+		ports = append(ports, p)
+	}
+	return ports
 }
 
 func (r *HazelcastReconciler) isServicePerPodReady(ctx context.Context, h *hazelcastv1alpha1.Hazelcast) bool {
@@ -409,40 +426,194 @@ func (r *HazelcastReconciler) reconcileConfigMap(ctx context.Context, h *hazelca
 		return fmt.Errorf("failed to set owner reference on ConfigMap: %w", err)
 	}
 
+	pms, skipped, err := persistedMaps(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+	for _, name := range skipped {
+		logger.Info("Map config is not persisted since its MapStore properties could not be read", "map", name)
+	}
+
+	realms, err := securityRealms(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+
+	// The Secret is updated first, the configuration in the ConfigMap imports it
+	err = r.reconcileSecretConfig(ctx, h, realms, pms, logger)
+	if err != nil {
+		return err
+	}
+
+	var conflicts []string
 	opResult, err := util.CreateOrUpdate(ctx, r.Client, cm, func() error {
-		cm.Data, err = hazelcastConfigMapData(r.Client, ctx, h)
+		cm.Data, conflicts, err = hazelcastConfigMapData(r.Client, ctx, h, pms)
 		return err
 	})
 	if opResult != controllerutil.OperationResultNone {
 		logger.Info("Operation result", "ConfigMap", h.Name, "result", opResult)
 	}
+	if err == nil {
+		// Saved with the next status update of the reconcile loop
+		h.Status.ConfigConflicts = conflicts
+	}
+	return err
+}
+
+// reconcileSecretConfig creates the Secret with the part of the Hazelcast configuration read from Secrets,
+// such as the credentials of the clients and the MapStore properties.
+// It is mounted to the members and imported by the configuration in the ConfigMap.
+func (r *HazelcastReconciler) reconcileSecretConfig(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, realms []config.Realm, pms []persistedMap, logger logr.Logger) error {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretConfigName(h),
+			Namespace: h.Namespace,
+			Labels:    labels(h),
+		},
+	}
+
+	err := controllerutil.SetControllerReference(h, s, r.Scheme)
+	if err != nil {
+		return fmt.Errorf("failed to set owner reference on secret config: %w", err)
+	}
+
+	yml, err := yaml.Marshal(config.SecretsWrapper{Hazelcast: secretConfig(realms, pms)})
+	if err != nil {
+		return err
+	}
+	opResult, err := util.CreateOrUpdate(ctx, r.Client, s, func() error {
+		s.Data = map[string][]byte{n.HazelcastSecretConfigKey: yml}
+		return nil
+	})
+	if opResult != controllerutil.OperationResultNone {
+		logger.Info("Operation result", "Secret", s.Name, "result", opResult)
+	}
 	return err
 }
 
-func hazelcastConfigMapData(c client.Client, ctx context.Context, h *hazelcastv1alpha1.Hazelcast) (map[string]string, error) {
+func secretConfigName(h *hazelcastv1alpha1.Hazelcast) string {
+	return h.Name + n.SecretConfigSuffix
+}
+
+func secretConfig(realms []config.Realm, pms []persistedMap) config.Secrets {
+	cfg := config.Secrets{}
+	if len(realms) != 0 {
+		cfg.Security = &config.SecretSecurity{Realms: realms}
+	}
+	for _, pm := range pms {
+		if len(pm.mapStoreProps) == 0 {
+			continue
+		}
+		if cfg.Map == nil {
+			cfg.Map = map[string]config.SecretMap{}
+		}
+		cfg.Map[pm.m.MapName()] = config.SecretMap{
+			MapStore: config.SecretMapStore{Properties: pm.mapStoreProps},
+		}
+	}
+	return cfg
+}
+
+// persistedMap is a Map whose config is persisted, together with the MapStore properties read from its Secret.
+type persistedMap struct {
+	m             hazelcastv1alpha1.Map
+	mapStoreProps map[string]string
+}
+
+// persistedMaps returns the Maps of the Hazelcast resource whose config is persisted.
+// The Maps whose MapStore properties cannot be read are skipped, so that they do not block the configuration of the cluster,
+// and their names are returned.
+func persistedMaps(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast) ([]persistedMap, []string, error) {
 	mapList := &hazelcastv1alpha1.MapList{}
-	err := c.List(ctx, mapList, client.MatchingFields{"hazelcastResourceName": h.Name})
+	err := c.List(ctx, mapList, client.InNamespace(h.Namespace), client.MatchingFields{"hazelcastResourceName": h.Name})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	var pms []persistedMap
+	var skipped []string
+	for _, m := range filterPersistedMaps(mapList.Items) {
+		props, err := mapStoreProperties(ctx, c, m.Namespace, propertiesSecretName(m.Spec.MapStore))
+		if err != nil {
+			skipped = append(skipped, m.MapName())
+			continue
+		}
+		pms = append(pms, persistedMap{m: m, mapStoreProps: props})
 	}
-	ml := filterPersistedMaps(mapList.Items)
+	return pms, skipped, nil
+}
 
+func hazelcastConfigMapData(c client.Client, ctx context.Context, h *hazelcastv1alpha1.Hazelcast, pms []persistedMap) (map[string]string, []string, error) {
 	cfg := hazelcastConfigMapStruct(h)
-	fillHazelcastConfigWithMaps(&cfg, ml)
+	fillHazelcastConfigWithMaps(&cfg, pms)
 
 	yml, err := yaml.Marshal(config.HazelcastWrapper{Hazelcast: cfg})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	custom, err := customConfig(ctx, c, h)
+	if err != nil {
+		return nil, nil, err
+	}
+	if custom == "" {
+		return map[string]string{n.HazelcastConfigKey: string(yml)}, nil, nil
+	}
+
+	yml, conflicts, err := mergeCustomConfig(yml, custom)
+	if err != nil {
+		return nil, nil, err
 	}
-	return map[string]string{"hazelcast.yaml": string(yml)}, nil
+	return map[string]string{n.HazelcastConfigKey: string(yml)}, conflicts, nil
+}
+
+// customConfig returns the content of the custom configuration referenced by the Hazelcast resource, or an empty string if there is none.
+func customConfig(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast) (string, error) {
+	if h.Spec.CustomConfigMapRef == "" {
+		return "", nil
+	}
+	cm := &corev1.ConfigMap{}
+	err := c.Get(ctx, types.NamespacedName{Name: h.Spec.CustomConfigMapRef, Namespace: h.Namespace}, cm)
+	if err != nil {
+		return "", fmt.Errorf("could not get custom config ConfigMap %s: %w", h.Spec.CustomConfigMapRef, err)
+	}
+	custom, ok := cm.Data[n.HazelcastConfigKey]
+	if !ok {
+		return "", fmt.Errorf("custom config ConfigMap %s does not contain %s key", h.Spec.CustomConfigMapRef, n.HazelcastConfigKey)
+	}
+	return custom, nil
+}
+
+func mergeCustomConfig(generated []byte, custom string) ([]byte, []string, error) {
+	gen := map[string]interface{}{}
+	if err := yaml.Unmarshal(generated, &gen); err != nil {
+		return nil, nil, err
+	}
+	cst := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(custom), &cst); err != nil {
+		return nil, nil, fmt.Errorf("custom config is not a valid YAML: %w", err)
+	}
+	if _, ok := cst["hazelcast"]; !ok && len(cst) != 0 {
+		return nil, nil, fmt.Errorf("custom config must have %q root element", "hazelcast")
+	}
+
+	merged, conflicts := config.Merge(gen, cst)
+	yml, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	return yml, conflicts, nil
 }
 
 func filterPersistedMaps(ml []hazelcastv1alpha1.Map) []hazelcastv1alpha1.Map {
 	l := make([]hazelcastv1alpha1.Map, 0)
 
 	for _, mp := range ml {
+		if mp.GetDeletionTimestamp() != nil && mp.Spec.DeletionPolicy == hazelcastv1alpha1.DeletionPolicyDelete {
+			continue
+		}
 		switch mp.Status.State {
-		case hazelcastv1alpha1.MapPersisting, hazelcastv1alpha1.MapSuccess:
+		case hazelcastv1alpha1.MapPersisting, hazelcastv1alpha1.MapSuccess, hazelcastv1alpha1.MapDrifted:
 			l = append(l, mp)
 		case hazelcastv1alpha1.MapFailed, hazelcastv1alpha1.MapPending:
 			if spec, ok := mp.Annotations[n.LastSuccessfulSpecAnnotation]; ok {
@@ -462,6 +633,7 @@ func filterPersistedMaps(ml []hazelcastv1alpha1.Map) []hazelcastv1alpha1.Map {
 
 func hazelcastConfigMapStruct(h *hazelcastv1alpha1.Hazelcast) config.Hazelcast {
 	cfg := config.Hazelcast{
+		Import: []string{path.Join(n.SecretConfigMountPath, n.HazelcastSecretConfigKey)},
 		Jet: config.Jet{
 			Enabled: &[]bool{true}[0],
 		},
@@ -502,6 +674,40 @@ func hazelcastConfigMapStruct(h *hazelcastv1alpha1.Hazelcast) config.Hazelcast {
 		cfg.ClusterName = h.Spec.ClusterName
 	}
 
+	if h.Spec.TLS.IsEnabled() {
+		cfg.Network.SSL = config.SSL{
+			Enabled:          &[]bool{true}[0],
+			FactoryClassName: "com.hazelcast.nio.ssl.OpenSSLEngineFactory",
+			Properties: map[string]string{
+				"protocol":                "TLSv1.2",
+				"keyFile":                 path.Join(n.TLSMountPath, corev1.TLSPrivateKeyKey),
+				"keyCertChainFile":        path.Join(n.TLSMountPath, corev1.TLSCertKey),
+				"trustCertCollectionFile": path.Join(n.TLSMountPath, n.TLSCAKey),
+			},
+		}
+	}
+
+	if h.Spec.Security.IsEnabled() {
+		cfg.Security = securityConfig(h)
+	}
+
+	if len(h.Spec.Properties) != 0 {
+		cfg.Properties = h.Spec.Properties
+	}
+
+	switch h.Spec.HighAvailabilityMode {
+	case hazelcastv1alpha1.HighAvailabilityModeNode:
+		cfg.PartitionGroup = config.PartitionGroup{
+			Enabled:   &[]bool{true}[0],
+			GroupType: "NODE_AWARE",
+		}
+	case hazelcastv1alpha1.HighAvailabilityModeZone:
+		cfg.PartitionGroup = config.PartitionGroup{
+			Enabled:   &[]bool{true}[0],
+			GroupType: "ZONE_AWARE",
+		}
+	}
+
 	if h.Spec.Persistence.IsEnabled() {
 		cfg.Persistence = config.Persistence{
 			Enabled:                   &[]bool{true}[0],
@@ -533,33 +739,141 @@ func clusterDataRecoveryPolicy(policyType hazelcastv1alpha1.DataRecoveryPolicyTy
 	return "FULL_RECOVERY_ONLY"
 }
 
-func fillHazelcastConfigWithMaps(cfg *config.Hazelcast, ml []hazelcastv1alpha1.Map) {
-	if len(ml) != 0 {
+func fillHazelcastConfigWithMaps(cfg *config.Hazelcast, pms []persistedMap) {
+	if len(pms) != 0 {
 		cfg.Map = map[string]config.Map{}
-		for _, mcfg := range ml {
-			cfg.Map[mcfg.MapName()] = createMapConfig(&mcfg.Spec)
+		for i := range pms {
+			cfg.Map[pms[i].m.MapName()] = createMapConfig(&pms[i].m)
 		}
 	}
 }
 
-func createMapConfig(ms *hazelcastv1alpha1.MapSpec) config.Map {
+// createMapConfig returns the persisted config of the map. The MapStore properties are not part of it, they are in the secret config.
+func createMapConfig(hm *hazelcastv1alpha1.Map) config.Map {
+	ms := &hm.Spec
 	m := config.Map{
 		BackupCount:       *ms.BackupCount,
-		AsyncBackupCount:  int32(0),
+		AsyncBackupCount:  ms.AsyncBackupCount,
 		TimeToLiveSeconds: *ms.TimeToLiveSeconds,
-		ReadBackupData:    false,
+		MaxIdleSeconds:    *ms.MaxIdleSeconds,
+		ReadBackupData:    ms.ReadBackupData,
 		Eviction: config.MapEviction{
 			Size:           *ms.Eviction.MaxSize,
 			MaxSizePolicy:  string(ms.Eviction.MaxSizePolicy),
 			EvictionPolicy: string(ms.Eviction.EvictionPolicy),
 		},
-		InMemoryFormat:    "BINARY",
+		InMemoryFormat:    string(ms.GetInMemoryFormat()),
 		Indexes:           copyMapIndexes(ms.Indexes),
-		StatisticsEnabled: true,
+		StatisticsEnabled: ms.IsStatisticsEnabled(),
 		HotRestart: config.MapHotRestart{
 			Enabled: ms.PersistenceEnabled,
 			Fsync:   false,
 		},
+		NearCache:      createNearCacheConfig(ms.NearCache),
+		EntryListeners: createEntryListenerConfigs(ms.EntryListeners),
+		QueryCaches:    createQueryCacheConfigs(ms.QueryCaches),
+	}
+	if ms.MerkleTree != nil {
+		m.MerkleTree = &config.MerkleTree{
+			Enabled: true,
+			Depth:   ms.MerkleTree.GetDepth(),
+		}
+	}
+	if ms.WanReplicationRef != nil {
+		m.WanReplicationRef = map[string]config.WanReplicationRef{
+			ms.WanReplicationRef.Name: {
+				MergePolicyClassName: ms.WanReplicationRef.GetMergePolicyClassName(),
+				RepublishingEnabled:  ms.WanReplicationRef.IsRepublishingEnabled(),
+				Filters:              ms.WanReplicationRef.Filters,
+			},
+		}
+	}
+	if ms.EventJournal != nil {
+		m.EventJournal = &config.EventJournal{
+			Enabled:           ms.EventJournal.Enabled,
+			Capacity:          ms.EventJournal.GetCapacity(),
+			TimeToLiveSeconds: ms.EventJournal.TimeToLiveSeconds,
+		}
+	}
+	if ms.MapStore != nil {
+		m.MapStore = &config.MapStore{
+			Enabled:           true,
+			ClassName:         ms.MapStore.ClassName,
+			WriteDelaySeconds: ms.MapStore.WriteDelaySeconds,
+			WriteBatchSize:    ms.MapStore.GetWriteBatchSize(),
+			WriteCoalescing:   ms.MapStore.IsWriteCoalescing(),
+			InitialLoadMode:   string(ms.MapStore.GetInitialLoadMode()),
+		}
+	}
+	return m
+}
+
+func createNearCacheConfig(nc *hazelcastv1alpha1.NearCacheConfig) *config.NearCache {
+	if nc == nil {
+		return nil
+	}
+	policy, size := nearCacheEviction(nc)
+	preloader := nearCachePreloader(nc)
+	return &config.NearCache{
+		InMemoryFormat:     string(nc.GetInMemoryFormat()),
+		InvalidateOnChange: nc.IsInvalidateOnChange(),
+		TimeToLiveSeconds:  nc.TimeToLiveSeconds,
+		MaxIdleSeconds:     nc.MaxIdleSeconds,
+		CacheLocalEntries:  nc.CacheLocalEntries,
+		Eviction: config.MapEviction{
+			Size:           size,
+			MaxSizePolicy:  n.DefaultNearCacheMaxSizePolicy,
+			EvictionPolicy: policy,
+		},
+		Preloader: config.NearCachePreloader{
+			Enabled:                  preloader.Enabled,
+			Directory:                preloader.Directory,
+			StoreInitialDelaySeconds: preloader.StoreInitialDelaySeconds,
+			StoreIntervalSeconds:     preloader.StoreIntervalSeconds,
+		},
+	}
+}
+
+func createEntryListenerConfigs(elcs []hazelcastv1alpha1.EntryListenerConfig) []config.EntryListener {
+	if len(elcs) == 0 {
+		return nil
+	}
+	els := make([]config.EntryListener, len(elcs))
+	for i, elc := range elcs {
+		els[i] = config.EntryListener{
+			ClassName:    elc.ClassName,
+			IncludeValue: elc.IsIncludeValue(),
+			Local:        elc.Local,
+		}
+	}
+	return els
+}
+
+func createQueryCacheConfigs(qcs []hazelcastv1alpha1.QueryCacheConfig) map[string]config.QueryCache {
+	if len(qcs) == 0 {
+		return nil
+	}
+	m := make(map[string]config.QueryCache, len(qcs))
+	for _, qc := range qcs {
+		holder := queryCacheConfigHolder(qc)
+		m[qc.Name] = config.QueryCache{
+			Predicate: config.QueryCachePredicate{
+				SQL:       qc.Predicate.SQL,
+				ClassName: qc.Predicate.ClassName,
+			},
+			BatchSize:      holder.BatchSize,
+			BufferSize:     holder.BufferSize,
+			Coalesce:       holder.Coalesce,
+			Populate:       holder.Populate,
+			IncludeValue:   holder.IncludeValue,
+			InMemoryFormat: holder.InMemoryFormat,
+			Eviction: config.MapEviction{
+				Size:           holder.EvictionConfigHolder.Size,
+				MaxSizePolicy:  holder.EvictionConfigHolder.MaxSizePolicy,
+				EvictionPolicy: holder.EvictionConfigHolder.EvictionPolicy,
+			},
+			Indexes: copyMapIndexes(qc.Indexes),
+		}
 	}
 	return m
 }
@@ -579,7 +893,95 @@ func copyMapIndexes(idx []hazelcastv1alpha1.IndexConfig) []config.MapIndex {
 	return ics
 }
 
-func (r *HazelcastReconciler) reconcileStatefulset(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) error {
+func (r *HazelcastReconciler) reconcilePodDisruptionBudget(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) error {
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metadata(h),
+	}
+
+	if !h.Spec.PodDisruptionBudget.IsEnabled() {
+		meta.RemoveStatusCondition(&h.Status.Conditions, hazelcastv1alpha1.EvictionAllowed)
+		err := r.Client.Delete(ctx, pdb)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PodDisruptionBudget: %w", err)
+		}
+		return nil
+	}
+
+	err := controllerutil.SetControllerReference(h, pdb, r.Scheme)
+	if err != nil {
+		return fmt.Errorf("failed to set owner reference on PodDisruptionBudget: %w", err)
+	}
+
+	maxUnavailable, err := r.maxUnavailable(ctx, h)
+	if err != nil {
+		return err
+	}
+	if err = setEvictionAllowedCondition(h, maxUnavailable); err != nil {
+		return err
+	}
+
+	opResult, err := util.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+		pdb.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: labels(h),
+		}
+		return nil
+	})
+	if opResult != controllerutil.OperationResultNone {
+		logger.Info("Operation result", "PodDisruptionBudget", h.Name, "result", opResult)
+	}
+	return err
+}
+
+// maxUnavailable returns the number of members that can be evicted at once without losing data.
+// Unless it is set explicitly, it equals to the lowest total of the backup and async backup counts of the Map resources,
+// but at least one member can be evicted, since the members migrate their partitions on graceful shutdown.
+func (r *HazelcastReconciler) maxUnavailable(ctx context.Context, h *hazelcastv1alpha1.Hazelcast) (intstr.IntOrString, error) {
+	if h.Spec.PodDisruptionBudget.MaxUnavailable != nil {
+		return *h.Spec.PodDisruptionBudget.MaxUnavailable, nil
+	}
+
+	mapList := &hazelcastv1alpha1.MapList{}
+	err := r.Client.List(ctx, mapList, client.InNamespace(h.Namespace), client.MatchingFields{"hazelcastResourceName": h.Name})
+	if err != nil {
+		return intstr.IntOrString{}, err
+	}
+
+	if len(mapList.Items) == 0 {
+		return intstr.FromInt(int(n.DefaultMapBackupCount + n.DefaultMapAsyncBackupCount)), nil
+	}
+	backups := int32(math.MaxInt32)
+	for _, m := range mapList.Items {
+		b := n.DefaultMapBackupCount
+		if m.Spec.BackupCount != nil {
+			b = *m.Spec.BackupCount
+		}
+		if b+m.Spec.AsyncBackupCount < backups {
+			backups = b + m.Spec.AsyncBackupCount
+		}
+	}
+	if backups < 1 {
+		backups = 1
+	}
+	return intstr.FromInt(int(backups)), nil
+}
+
+// setEvictionAllowedCondition reports whether the PodDisruptionBudget allows evicting at least one member.
+func setEvictionAllowedCondition(h *hazelcastv1alpha1.Hazelcast, maxUnavailable intstr.IntOrString) error {
+	allowed, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, int(*h.Spec.ClusterSize), true)
+	if err != nil {
+		return fmt.Errorf("invalid podDisruptionBudget.maxUnavailable: %w", err)
+	}
+	if allowed == 0 && *h.Spec.ClusterSize != 0 {
+		setConditionFalse(h, hazelcastv1alpha1.EvictionAllowed, reasonEvictionBlocked,
+			"PodDisruptionBudget does not allow evicting any member, node drains and cluster upgrades are blocked")
+		return nil
+	}
+	setConditionTrue(h, hazelcastv1alpha1.EvictionAllowed, reasonReady, "")
+	return nil
+}
+
+func (r *HazelcastReconciler) reconcileStatefulset(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, replicas, partition int32, logger logr.Logger) error {
 	ls := labels(h)
 	sts := &appsv1.StatefulSet{
 		ObjectMeta: metadata(h),
@@ -600,18 +1002,14 @@ func (r *HazelcastReconciler) reconcileStatefulset(ctx context.Context, h *hazel
 						RunAsUser:    &[]int64{65534}[0],
 					},
 					Containers: []v1.Container{{
-						Name: n.Hazelcast,
-						Ports: []v1.ContainerPort{{
-							ContainerPort: n.DefaultHzPort,
-							Name:          n.Hazelcast,
-							Protocol:      v1.ProtocolTCP,
-						}},
+						Name:  n.Hazelcast,
+						Ports: containerPorts(h),
 						LivenessProbe: &v1.Probe{
 							Handler: v1.Handler{
 								HTTPGet: &v1.HTTPGetAction{
 									Path:   "/hazelcast/health/node-state",
 									Port:   intstr.FromInt(n.DefaultHzPort),
-									Scheme: corev1.URISchemeHTTP,
+									Scheme: probeScheme(h),
 								},
 							},
 							InitialDelaySeconds: 0,
@@ -625,7 +1023,7 @@ func (r *HazelcastReconciler) reconcileStatefulset(ctx context.Context, h *hazel
 								HTTPGet: &v1.HTTPGetAction{
 									Path:   "/hazelcast/health/node-state",
 									Port:   intstr.FromInt(n.DefaultHzPort),
-									Scheme: corev1.URISchemeHTTP,
+									Scheme: probeScheme(h),
 								},
 							},
 							InitialDelaySeconds: 0,
@@ -699,28 +1097,64 @@ func (r *HazelcastReconciler) reconcileStatefulset(ctx context.Context, h *hazel
 		return fmt.Errorf("failed to set owner reference on Statefulset: %w", err)
 	}
 
+	// Members read the custom configuration only on startup
+	custom, err := customConfig(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+	// And the credentials as well
+	realms, err := securityRealms(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+	securitySum, err := securityChecksum(realms)
+	if err != nil {
+		return err
+	}
+	// And the certificates, which can be rotated within the same Secret
+	ts, err := tlsSecret(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+
 	opResult, err := util.CreateOrUpdate(ctx, r.Client, sts, func() error {
-		sts.Spec.Replicas = h.Spec.ClusterSize
+		sts.Spec.Replicas = &replicas
+		sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: &partition,
+			},
+		}
 		sts.ObjectMeta.Annotations = statefulSetAnnotations(h)
 		sts.Spec.Template.Annotations, err = podAnnotations(h)
 		if err != nil {
 			return err
 		}
+		if custom != "" {
+			sts.Spec.Template.Annotations[n.CustomConfigChecksum] = fmt.Sprint(crc32.ChecksumIEEE([]byte(custom)))
+		}
+		if securitySum != "" {
+			sts.Spec.Template.Annotations[n.SecurityChecksum] = securitySum
+		}
+		if ts != nil {
+			sts.Spec.Template.Annotations[n.TLSChecksum] = secretChecksum(ts)
+		}
 		sts.Spec.Template.Spec.ImagePullSecrets = h.Spec.ImagePullSecrets
 		sts.Spec.Template.Spec.Containers[0].Image = h.DockerImage()
 		sts.Spec.Template.Spec.Containers[0].Env = env(h)
+		sts.Spec.Template.Spec.Containers[0].Ports = containerPorts(h)
 		sts.Spec.Template.Spec.Containers[0].ImagePullPolicy = h.Spec.ImagePullPolicy
 
 		if h.Spec.Scheduling != nil {
 			sts.Spec.Template.Spec.Affinity = h.Spec.Scheduling.Affinity
 			sts.Spec.Template.Spec.Tolerations = h.Spec.Scheduling.Tolerations
 			sts.Spec.Template.Spec.NodeSelector = h.Spec.Scheduling.NodeSelector
-			sts.Spec.Template.Spec.TopologySpreadConstraints = h.Spec.Scheduling.TopologySpreadConstraints
+			sts.Spec.Template.Spec.TopologySpreadConstraints = topologySpreadConstraints(h)
 		} else {
 			sts.Spec.Template.Spec.Affinity = nil
 			sts.Spec.Template.Spec.Tolerations = nil
 			sts.Spec.Template.Spec.NodeSelector = nil
-			sts.Spec.Template.Spec.TopologySpreadConstraints = nil
+			sts.Spec.Template.Spec.TopologySpreadConstraints = topologySpreadConstraints(h)
 		}
 
 		if h.Spec.Resources != nil {
@@ -897,7 +1331,7 @@ func restoreAgentContainer(h *hazelcastv1alpha1.Hazelcast, provider string) v1.C
 }
 
 func volumes(h *hazelcastv1alpha1.Hazelcast) []v1.Volume {
-	return []v1.Volume{
+	vols := []v1.Volume{
 		{
 			Name: n.HazelcastStorageName,
 			VolumeSource: v1.VolumeSource{
@@ -909,6 +1343,25 @@ func volumes(h *hazelcastv1alpha1.Hazelcast) []v1.Volume {
 			},
 		},
 	}
+	vols = append(vols, v1.Volume{
+		Name: n.SecretConfigVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: secretConfigName(h),
+			},
+		},
+	})
+	if h.Spec.TLS.IsEnabled() {
+		vols = append(vols, v1.Volume{
+			Name: n.TLSVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: h.Spec.TLS.SecretName,
+				},
+			},
+		})
+	}
+	return vols
 }
 
 func hostPathVolume(h *hazelcastv1alpha1.Hazelcast) v1.Volume {
@@ -950,6 +1403,11 @@ func volumeMount(h *hazelcastv1alpha1.Hazelcast) []corev1.VolumeMount {
 			Name:      n.HazelcastStorageName,
 			MountPath: n.HazelcastMountPath,
 		},
+		{
+			Name:      n.SecretConfigVolumeName,
+			MountPath: n.SecretConfigMountPath,
+			ReadOnly:  true,
+		},
 	}
 	if h.Spec.Persistence.IsEnabled() {
 		mounts = append(mounts, v1.VolumeMount{
@@ -957,9 +1415,24 @@ func volumeMount(h *hazelcastv1alpha1.Hazelcast) []corev1.VolumeMount {
 			MountPath: h.Spec.Persistence.BaseDir,
 		})
 	}
+	if h.Spec.TLS.IsEnabled() {
+		mounts = append(mounts, v1.VolumeMount{
+			Name:      n.TLSVolumeName,
+			MountPath: n.TLSMountPath,
+			ReadOnly:  true,
+		})
+	}
 	return mounts
 }
 
+// probeScheme returns the scheme used by the health check probes, members accept only HTTPS when TLS is enabled.
+func probeScheme(h *hazelcastv1alpha1.Hazelcast) corev1.URIScheme {
+	if h.Spec.TLS.IsEnabled() {
+		return corev1.URISchemeHTTPS
+	}
+	return corev1.URISchemeHTTP
+}
+
 // checkHotRestart checks if the persistence feature and AutoForceStart is enabled, pods are failing,
 // and the cluster is in the PASSIVE mode and performs the Force Start action.
 func (r *HazelcastReconciler) checkHotRestart(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) error {
@@ -971,7 +1444,11 @@ func (r *HazelcastReconciler) checkHotRestart(ctx context.Context, h *hazelcastv
 		if !member.Ready && member.Reason == "CrashLoopBackOff" {
 			logger.Info("Member is crashing with CrashLoopBackOff.",
 				"RestartCounts", member.RestartCount, "Message", member.Message)
-			rest := NewRestClient(h)
+			conn, err := newConnectionConfig(ctx, r.Client, h)
+			if err != nil {
+				return err
+			}
+			rest := NewRestClient(h, conn)
 			state, err := rest.GetState(ctx)
 			if err != nil {
 				return err
@@ -983,8 +1460,11 @@ func (r *HazelcastReconciler) checkHotRestart(ctx context.Context, h *hazelcastv
 			}
 			err = rest.ForceStart(ctx)
 			if err != nil {
+				r.Recorder.Eventf(h, corev1.EventTypeWarning, reasonForceStartFailed, "Force Start action failed: %s", err)
 				return err
 			}
+			metrics.IncForceStarts(h)
+			r.Recorder.Eventf(h, corev1.EventTypeNormal, reasonForceStart, "Force Start action triggered, member %s is crashing", member.PodName)
 		}
 	}
 	return nil
@@ -1016,7 +1496,11 @@ func (r *HazelcastReconciler) ensureClusterActive(ctx context.Context, h *hazelc
 		}
 	}
 
-	rest := NewRestClient(h)
+	conn, err := newConnectionConfig(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+	rest := NewRestClient(h, conn)
 	state, err := rest.GetState(ctx)
 	if err != nil {
 		return err
@@ -1024,14 +1508,54 @@ func (r *HazelcastReconciler) ensureClusterActive(ctx context.Context, h *hazelc
 	if state != "passive" {
 		return nil
 	}
-	return rest.ChangeState(ctx, Active)
+	err = rest.ChangeState(ctx, Active)
+	if err != nil {
+		r.Recorder.Eventf(h, corev1.EventTypeWarning, reasonChangeStateFailed, "Could not change the cluster state from PASSIVE to ACTIVE: %s", err)
+		return err
+	}
+	r.Recorder.Event(h, corev1.EventTypeNormal, reasonChangeState, "Cluster state changed from PASSIVE to ACTIVE after the restore")
+	return nil
+}
+
+// topologySpreadConstraints returns the user defined constraints completed with the one required by the high availability mode.
+func topologySpreadConstraints(h *hazelcastv1alpha1.Hazelcast) []v1.TopologySpreadConstraint {
+	var tsc []v1.TopologySpreadConstraint
+	if h.Spec.Scheduling != nil {
+		tsc = h.Spec.Scheduling.TopologySpreadConstraints
+	}
+
+	var key string
+	switch h.Spec.HighAvailabilityMode {
+	case hazelcastv1alpha1.HighAvailabilityModeNode:
+		key = v1.LabelHostname
+	case hazelcastv1alpha1.HighAvailabilityModeZone:
+		key = v1.LabelTopologyZone
+	default:
+		return tsc
+	}
+
+	for _, c := range tsc {
+		if c.TopologyKey == key {
+			return tsc
+		}
+	}
+	return append(append([]v1.TopologySpreadConstraint{}, tsc...), v1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       key,
+		WhenUnsatisfiable: v1.DoNotSchedule,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: labels(h),
+		},
+	})
 }
 
 func env(h *hazelcastv1alpha1.Hazelcast) []v1.EnvVar {
+	javaOpts := append([]string{fmt.Sprintf("-Dhazelcast.config=%s/hazelcast.yaml", n.HazelcastMountPath)}, jvmArgs(h)...)
+	javaOpts = append(javaOpts, metricsJavaOpts(h)...)
 	envs := []v1.EnvVar{
 		{
 			Name:  "JAVA_OPTS",
-			Value: fmt.Sprintf("-Dhazelcast.config=%s/hazelcast.yaml", n.HazelcastMountPath),
+			Value: strings.Join(javaOpts, " "),
 		},
 		{
 			Name:  "HZ_PARDOT_ID",
@@ -1086,6 +1610,8 @@ func podAnnotations(h *hazelcastv1alpha1.Hazelcast) (map[string]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	// JVM arguments are applied only on startup as well
+	cfgYaml = append(cfgYaml, strings.Join(jvmArgs(h), " ")...)
 	ans[n.CurrentHazelcastConfigForcingRestartChecksum] = fmt.Sprint(crc32.ChecksumIEEE(cfgYaml))
 
 	return ans, nil
@@ -1105,16 +1631,21 @@ func (r *HazelcastReconciler) updateLastSuccessfulConfiguration(ctx context.Cont
 		return err
 	}
 
-	opResult, err := util.CreateOrUpdate(ctx, r.Client, h, func() error {
-		if h.ObjectMeta.Annotations == nil {
+	// CreateOrUpdate re-reads the object, so it is done on a copy to keep the status set during this reconcile
+	hc := h.DeepCopy()
+	opResult, err := util.CreateOrUpdate(ctx, r.Client, hc, func() error {
+		if hc.ObjectMeta.Annotations == nil {
 			ans := map[string]string{}
-			h.ObjectMeta.Annotations = ans
+			hc.ObjectMeta.Annotations = ans
 		}
-		h.ObjectMeta.Annotations[n.LastSuccessfulSpecAnnotation] = string(hs)
+		hc.ObjectMeta.Annotations[n.LastSuccessfulSpecAnnotation] = string(hs)
 		return nil
 	})
 	if opResult != controllerutil.OperationResultNone {
 		logger.Info("Operation result", "Hazelcast Annotation", h.Name, "result", opResult)
 	}
+	if err == nil {
+		h.ObjectMeta = hc.ObjectMeta
+	}
 	return err
 }
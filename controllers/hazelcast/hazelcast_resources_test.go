@@ -2,13 +2,21 @@ package hazelcast
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
 )
 
 func Test_clientShutdownWhenConnectionNotEstablished(t *testing.T) {
@@ -32,3 +40,311 @@ func reconcilerWithCR(h *hazelcastv1alpha1.Hazelcast) HazelcastReconciler {
 		Client: fakeClient(h),
 	}
 }
+
+func Test_securityCredentialsKeptOutOfJavaOpts(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hazelcast",
+			Namespace: "default",
+		},
+		Spec: hazelcastv1alpha1.HazelcastSpec{
+			Security: &hazelcastv1alpha1.SecurityConfiguration{
+				Clients: []hazelcastv1alpha1.ClientIdentity{
+					{Name: "app", SecretName: "app-credentials"},
+				},
+			},
+		},
+	}
+	identity := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: h.Name + n.OperatorIdentitySuffix, Namespace: "default"},
+		Data:       map[string][]byte{n.SecurityUsernameKey: []byte(n.OperatorName), n.SecurityPasswordKey: []byte("operator-pass")},
+	}
+	app := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-credentials", Namespace: "default"},
+		Data:       map[string][]byte{n.SecurityUsernameKey: []byte("app"), n.SecurityPasswordKey: []byte("app-pass")},
+	}
+	c := fakeClient(h, identity, app)
+	ctx := context.Background()
+
+	for _, e := range env(h) {
+		if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil && e.ValueFrom.SecretKeyRef.Name == "app-credentials" {
+			t.Errorf("Expected the client credentials not to be passed as environment variables")
+		}
+		if e.Name == "JAVA_OPTS" && strings.Contains(e.Value, "security") {
+			t.Errorf("Expected no credentials in JAVA_OPTS: %s", e.Value)
+		}
+	}
+
+	realms, err := securityRealms(ctx, c, h)
+	if err != nil {
+		t.Fatalf("securityRealms() error = %v", err)
+	}
+	users := realms[0].Authentication.Simple.Users
+	if len(users) != 2 || users[0].Password != "operator-pass" || users[1].Username != "app" || users[1].Password != "app-pass" {
+		t.Errorf("Unexpected users in the security realm: %+v", users)
+	}
+	sum, err := securityChecksum(realms)
+	if err != nil {
+		t.Fatalf("securityChecksum() error = %v", err)
+	}
+
+	// Rotating the credentials of a client restarts the members
+	app.Data[n.SecurityPasswordKey] = []byte("rotated")
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	realms, err = securityRealms(ctx, c, h)
+	if err != nil {
+		t.Fatalf("securityRealms() error = %v", err)
+	}
+	rotated, err := securityChecksum(realms)
+	if err != nil {
+		t.Fatalf("securityChecksum() error = %v", err)
+	}
+	if rotated == sum {
+		t.Errorf("Expected the security checksum to change when the credentials are rotated")
+	}
+
+	// Token identities are rejected rather than left out of the realm
+	app.Data = map[string][]byte{n.SecurityTokenKey: []byte("app-token")}
+	if err := c.Update(ctx, app); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = securityRealms(ctx, c, h); err == nil || !strings.Contains(err.Error(), "token identities are not supported") {
+		t.Errorf("Expected the token identity to be rejected, got %v", err)
+	}
+}
+
+func Test_jvmArgsHeapDerivedFromMemoryLimit(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		Spec: hazelcastv1alpha1.HazelcastSpec{
+			Resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+	if got := strings.Join(jvmArgs(h), " "); got != "-Xmx819m" {
+		t.Errorf("jvmArgs() = %q, want %q", got, "-Xmx819m")
+	}
+
+	h.Spec.JVM = &hazelcastv1alpha1.JVMConfiguration{
+		Memory: &hazelcastv1alpha1.JVMMemoryConfiguration{
+			MaxHeap: &[]resource.Quantity{resource.MustParse("512Mi")}[0],
+		},
+		GC:   &hazelcastv1alpha1.JVMGCConfiguration{Collector: hazelcastv1alpha1.GCTypeG1},
+		Args: []string{"-Dhazelcast.diagnostics.enabled=true"},
+	}
+	want := "-Xmx512m -XX:+UseG1GC -Dhazelcast.diagnostics.enabled=true"
+	if got := strings.Join(jvmArgs(h), " "); got != want {
+		t.Errorf("jvmArgs() = %q, want %q", got, want)
+	}
+}
+
+func Test_filterPersistedMapsSkipsDeletedMaps(t *testing.T) {
+	now := metav1.Now()
+	maps := []hazelcastv1alpha1.Map{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "active"},
+			Spec:       hazelcastv1alpha1.MapSpec{DeletionPolicy: hazelcastv1alpha1.DeletionPolicyDelete},
+			Status:     hazelcastv1alpha1.MapStatus{State: hazelcastv1alpha1.MapSuccess},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "retained", DeletionTimestamp: &now},
+			Spec:       hazelcastv1alpha1.MapSpec{DeletionPolicy: hazelcastv1alpha1.DeletionPolicyRetain},
+			Status:     hazelcastv1alpha1.MapStatus{State: hazelcastv1alpha1.MapSuccess},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleted", DeletionTimestamp: &now},
+			Spec:       hazelcastv1alpha1.MapSpec{DeletionPolicy: hazelcastv1alpha1.DeletionPolicyDelete},
+			Status:     hazelcastv1alpha1.MapStatus{State: hazelcastv1alpha1.MapTerminating},
+		},
+	}
+
+	var names []string
+	for _, m := range filterPersistedMaps(maps) {
+		names = append(names, m.Name)
+	}
+	if strings.Join(names, ",") != "active,retained" {
+		t.Errorf("filterPersistedMaps() = %v, want [active retained]", names)
+	}
+}
+
+func Test_mapStorePropertiesKeptOutOfConfigMap(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{Name: "hazelcast", Namespace: "default"},
+	}
+	mapWithSecret := func(name, secret string) *hazelcastv1alpha1.Map {
+		return &hazelcastv1alpha1.Map{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: hazelcastv1alpha1.MapSpec{
+				HazelcastResourceName: h.Name,
+				BackupCount:           &[]int32{1}[0],
+				TimeToLiveSeconds:     &[]int32{0}[0],
+				MaxIdleSeconds:        &[]int32{0}[0],
+				Eviction:              &hazelcastv1alpha1.EvictionConfig{MaxSize: &[]int32{0}[0]},
+				MapStore: &hazelcastv1alpha1.MapStoreConfig{
+					ClassName:            "com.example.Store",
+					PropertiesSecretName: secret,
+				},
+			},
+			Status: hazelcastv1alpha1.MapStatus{State: hazelcastv1alpha1.MapSuccess},
+		}
+	}
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+	c := fakeClient(h, s, mapWithSecret("stored", "db"), mapWithSecret("broken", "missing"))
+	r := HazelcastReconciler{Client: c, Scheme: c.Scheme()}
+	ctx := context.Background()
+
+	if err := r.reconcileConfigMap(ctx, h, ctrl.Log); err != nil {
+		t.Fatalf("reconcileConfigMap() error = %v", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: h.Name, Namespace: h.Namespace}, cm); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cfg := cm.Data[n.HazelcastConfigKey]
+	if strings.Contains(cfg, "s3cr3t") {
+		t.Errorf("Expected the MapStore properties to be kept out of the ConfigMap, got %s", cfg)
+	}
+	if !strings.Contains(cfg, "stored:") || strings.Contains(cfg, "broken:") {
+		t.Errorf("Expected only the Map with a readable Secret to be persisted, got %s", cfg)
+	}
+
+	sc := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: h.Name + n.SecretConfigSuffix, Namespace: h.Namespace}, sc); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(sc.Data[n.HazelcastSecretConfigKey]), "password: s3cr3t") {
+		t.Errorf("Expected the MapStore properties in the secret config, got %s", sc.Data[n.HazelcastSecretConfigKey])
+	}
+}
+
+func Test_maxUnavailableFromMapBackupCount(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{Name: "hazelcast", Namespace: "default"},
+		Spec: hazelcastv1alpha1.HazelcastSpec{
+			ClusterSize:         &[]int32{3}[0],
+			PodDisruptionBudget: &hazelcastv1alpha1.PodDisruptionBudgetConfiguration{Enabled: true},
+		},
+	}
+	mapWithBackups := func(name, namespace string, backups *int32, asyncBackups int32) *hazelcastv1alpha1.Map {
+		return &hazelcastv1alpha1.Map{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: hazelcastv1alpha1.MapSpec{
+				HazelcastResourceName: h.Name,
+				BackupCount:           backups,
+				AsyncBackupCount:      asyncBackups,
+			},
+		}
+	}
+	count := func(c int32) *int32 { return &c }
+	tests := []struct {
+		name string
+		maps []client.Object
+		want int
+	}{
+		{name: "No maps", want: 1},
+		{
+			name: "Maps of other namespaces ignored",
+			maps: []client.Object{mapWithBackups("other", "other", count(0), 0), mapWithBackups("map", "default", count(2), 0)},
+			want: 2,
+		},
+		{
+			name: "Async backups counted",
+			maps: []client.Object{mapWithBackups("map", "default", count(1), 2), mapWithBackups("map-2", "default", count(2), 2)},
+			want: 3,
+		},
+		{
+			name: "Lowest total regardless of order",
+			maps: []client.Object{mapWithBackups("map-1", "default", nil, 0), mapWithBackups("map-2", "default", count(3), 0)},
+			want: 1,
+		},
+		{
+			name: "At least one member evictable",
+			maps: []client.Object{mapWithBackups("map", "default", count(0), 0)},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fakeClient(append(tt.maps, h)...)
+			r := HazelcastReconciler{Client: c, Scheme: c.Scheme()}
+			mu, err := r.maxUnavailable(context.Background(), h)
+			if err != nil {
+				t.Fatalf("maxUnavailable() error = %v", err)
+			}
+			if mu.IntValue() != tt.want {
+				t.Errorf("maxUnavailable() = %v, want %v", mu.IntValue(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_evictionAllowedCondition(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxUnavailable intstr.IntOrString
+		want           metav1.ConditionStatus
+	}{
+		{name: "Percentage rounded up to a member", maxUnavailable: intstr.FromString("10%"), want: metav1.ConditionTrue},
+		{name: "No member evictable", maxUnavailable: intstr.FromInt(0), want: metav1.ConditionFalse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hazelcastv1alpha1.Hazelcast{Spec: hazelcastv1alpha1.HazelcastSpec{ClusterSize: &[]int32{3}[0]}}
+			if err := setEvictionAllowedCondition(h, tt.maxUnavailable); err != nil {
+				t.Fatalf("setEvictionAllowedCondition() error = %v", err)
+			}
+			c := meta.FindStatusCondition(h.Status.Conditions, hazelcastv1alpha1.EvictionAllowed)
+			if c == nil || c.Status != tt.want {
+				t.Errorf("EvictionAllowed condition = %v, want status %s", c, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tlsChecksumChangesOnRotation(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{Name: "hazelcast", Namespace: "default"},
+		Spec: hazelcastv1alpha1.HazelcastSpec{
+			ClusterSize: &[]int32{3}[0],
+			TLS:         &hazelcastv1alpha1.TLSConfiguration{SecretName: "tls"},
+		},
+	}
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: "default"},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key"), n.TLSCAKey: []byte("ca")},
+	}
+	c := fakeClient(h, s)
+	r := HazelcastReconciler{Client: c, Scheme: c.Scheme()}
+	ctx := context.Background()
+
+	checksum := func() string {
+		if err := r.reconcileStatefulset(ctx, h, 3, 0, ctrl.Log); err != nil {
+			t.Fatalf("reconcileStatefulset() error = %v", err)
+		}
+		sts := &appsv1.StatefulSet{}
+		if err := c.Get(ctx, types.NamespacedName{Name: h.Name, Namespace: h.Namespace}, sts); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return sts.Spec.Template.Annotations[n.TLSChecksum]
+	}
+
+	before := checksum()
+	if before == "" || before != checksum() {
+		t.Fatalf("Expected a stable TLS checksum, got %q", before)
+	}
+	s.Data[corev1.TLSCertKey] = []byte("rotated")
+	if err := c.Update(ctx, s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if checksum() == before {
+		t.Errorf("Expected the TLS checksum to change when the certificate is rotated in the same Secret")
+	}
+}
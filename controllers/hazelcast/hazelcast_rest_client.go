@@ -19,6 +19,8 @@ const (
 	getState    = "/hazelcast/rest/management/cluster/state"
 	forceStart  = "/hazelcast/rest/management/cluster/forceStart"
 	hotBackup   = "/hazelcast/rest/management/cluster/hotBackup"
+	health      = "/hazelcast/health"
+	version     = "/hazelcast/rest/management/cluster/version"
 )
 
 type ClusterState string
@@ -34,21 +36,49 @@ const (
 type RestClient struct {
 	url         string
 	clusterName string
+	credentials *credentials
+	httpClient  *http.Client
 }
 
 type stateResponse struct {
 	State string `json:"state"`
 }
 
-func NewRestClient(h *v1alpha1.Hazelcast) *RestClient {
+type versionResponse struct {
+	Version string `json:"version"`
+}
+
+type healthResponse struct {
+	ClusterSafe        bool  `json:"clusterSafe"`
+	MigrationQueueSize int64 `json:"migrationQueueSize"`
+}
+
+func NewRestClient(h *v1alpha1.Hazelcast, conn connectionConfig) *RestClient {
+	httpClient := http.DefaultClient
+	if conn.tls != nil {
+		httpClient = &http.Client{
+			Transport: &http.Transport{TLSClientConfig: conn.tls},
+		}
+	}
 	return &RestClient{
 		url:         restUrl(h),
 		clusterName: h.Spec.ClusterName,
+		credentials: conn.credentials,
+		httpClient:  httpClient,
+	}
+}
+
+// authData returns the first two parameters of the management endpoints.
+// These are the cluster name and an empty password, or the operator credentials when security is enabled.
+func (c *RestClient) authData() string {
+	if c.credentials != nil {
+		return fmt.Sprintf("%s&%s", c.credentials.username, c.credentials.password)
 	}
+	return fmt.Sprintf("%s&", c.clusterName)
 }
 
 func (c *RestClient) ForceStart(ctx context.Context) error {
-	d := fmt.Sprintf("%s&", c.clusterName)
+	d := c.authData()
 	ctxT, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	req, err := postRequest(ctxT, d, c.url, forceStart)
@@ -69,7 +99,7 @@ func (c *RestClient) ForceStart(ctx context.Context) error {
 }
 
 func (c *RestClient) GetState(ctx context.Context) (string, error) {
-	d := fmt.Sprintf("%s&", c.clusterName)
+	d := c.authData()
 	ctxT, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	req, err := postRequest(ctxT, d, c.url, getState)
@@ -98,7 +128,7 @@ func (c *RestClient) GetState(ctx context.Context) (string, error) {
 }
 
 func (c *RestClient) ChangeState(ctx context.Context, state ClusterState) error {
-	d := fmt.Sprintf("%s&&%s", c.clusterName, state)
+	d := fmt.Sprintf("%s&%s", c.authData(), state)
 	ctxT, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	req, err := postRequest(ctxT, d, c.url, changeState)
@@ -122,7 +152,7 @@ func (c *RestClient) ChangeState(ctx context.Context, state ClusterState) error
 }
 
 func (c *RestClient) HotBackup(ctx context.Context) error {
-	d := fmt.Sprintf("%s&", c.clusterName)
+	d := c.authData()
 	ctxT, cancel := context.WithTimeout(ctx, time.Minute)
 	defer cancel()
 	req, err := postRequest(ctxT, d, c.url, hotBackup)
@@ -137,8 +167,73 @@ func (c *RestClient) HotBackup(ctx context.Context) error {
 	return nil
 }
 
+func (c *RestClient) GetClusterVersion(ctx context.Context) (string, error) {
+	ctxT, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctxT, "GET", c.url+version, nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := c.executeRequest(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	v := &versionResponse{}
+	err = json.NewDecoder(res.Body).Decode(v)
+	if err != nil {
+		return "", err
+	}
+	return v.Version, nil
+}
+
+func (c *RestClient) ChangeClusterVersion(ctx context.Context, clusterVersion string) error {
+	d := fmt.Sprintf("%s&%s", c.authData(), clusterVersion)
+	ctxT, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := postRequest(ctxT, d, c.url, version)
+	if err != nil {
+		return err
+	}
+	res, err := c.executeRequest(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	var rBody map[string]string
+	err = json.NewDecoder(res.Body).Decode(&rBody)
+	if err != nil {
+		return err
+	}
+	if s := rBody["status"]; s != "success" {
+		return fmt.Errorf("unexpected cluster version change status: %s, %s", s, rBody["message"])
+	}
+	return nil
+}
+
+// IsClusterSafe returns true if all the partition replicas of the cluster are in sync and no migrations are in progress.
+func (c *RestClient) IsClusterSafe(ctx context.Context) (bool, error) {
+	ctxT, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctxT, "GET", c.url+health, nil)
+	if err != nil {
+		return false, err
+	}
+	res, err := c.executeRequest(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	h := &healthResponse{}
+	err = json.NewDecoder(res.Body).Decode(h)
+	if err != nil {
+		return false, err
+	}
+	return h.ClusterSafe && h.MigrationQueueSize == 0, nil
+}
+
 func (c *RestClient) executeRequest(req *http.Request) (*http.Response, error) {
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return res, err
 	}
@@ -0,0 +1,95 @@
+package hazelcast
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+)
+
+// replicas returns the number of replicas the StatefulSet should have in the current reconcile loop.
+// When the cluster size is decreased, the members are removed one by one and only when the cluster is safe,
+// so that the partitions of the leaving member are migrated before the next one leaves.
+func (r *HazelcastReconciler) replicas(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) (int32, error) {
+	sts := &appsv1.StatefulSet{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: h.Name, Namespace: h.Namespace}, sts)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return *h.Spec.ClusterSize, nil
+		}
+		return 0, err
+	}
+
+	current := *sts.Spec.Replicas
+	if *h.Spec.ClusterSize >= current {
+		return *h.Spec.ClusterSize, nil
+	}
+
+	if sts.Status.ObservedGeneration != sts.Generation || sts.Status.Replicas != current {
+		logger.Info("Waiting for the previous member to leave the cluster", "replicas", current)
+		return current, nil
+	}
+
+	conn, err := newConnectionConfig(ctx, r.Client, h)
+	if err != nil {
+		return 0, err
+	}
+	safe, err := NewRestClient(h, conn).IsClusterSafe(ctx)
+	setClusterSafeCondition(h, safe, err)
+	if err != nil {
+		logger.Error(err, "Could not check if the cluster is safe, postponing the scale down")
+		r.Recorder.Eventf(h, corev1.EventTypeWarning, reasonScalingPostponed, "Scale down postponed, could not check if the cluster is safe: %s", err)
+		return current, nil
+	}
+	if !safe {
+		logger.Info("Cluster is not safe, postponing the scale down", "replicas", current)
+		return current, nil
+	}
+	return current - 1, nil
+}
+
+// maxScalingRetryAfter is the maximum time to requeue while the cluster safety cannot be checked
+const maxScalingRetryAfter = 5 * time.Minute
+
+func scalingMessage(h *hazelcastv1alpha1.Hazelcast, replicas int32) string {
+	msg := fmt.Sprintf("Scaling down the cluster to %d members, %d members remaining", *h.Spec.ClusterSize, replicas)
+	if c := clusterSafeCheckFailure(h); c != nil {
+		msg += fmt.Sprintf(", postponed since the cluster safety could not be checked: %s", c.Message)
+	}
+	return msg
+}
+
+// scalingRetryAfter returns the time to requeue while scaling down.
+// While the cluster safety cannot be checked, the retries are backed off by the time passed since the first failed check.
+func scalingRetryAfter(h *hazelcastv1alpha1.Hazelcast, now time.Time) time.Duration {
+	c := clusterSafeCheckFailure(h)
+	if c == nil {
+		return retryAfter
+	}
+	d := now.Sub(c.LastTransitionTime.Time)
+	if d < retryAfter {
+		return retryAfter
+	}
+	if d > maxScalingRetryAfter {
+		return maxScalingRetryAfter
+	}
+	return d
+}
+
+// clusterSafeCheckFailure returns the ClusterSafe condition if the last cluster safety check failed, nil otherwise.
+func clusterSafeCheckFailure(h *hazelcastv1alpha1.Hazelcast) *metav1.Condition {
+	c := meta.FindStatusCondition(h.Status.Conditions, hazelcastv1alpha1.ClusterSafe)
+	if c == nil || c.Status != metav1.ConditionUnknown {
+		return nil
+	}
+	return c
+}
@@ -0,0 +1,107 @@
+package hazelcast
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+)
+
+func Test_replicasDecreasedOneByOneWhenClusterIsSafe(t *testing.T) {
+	tests := []struct {
+		name         string
+		stsReplicas  int32
+		podReplicas  int32
+		clusterSafe  bool
+		checkFails   bool
+		wantReplicas int32
+	}{
+		{name: "Member removed when cluster is safe", stsReplicas: 3, podReplicas: 3, clusterSafe: true, wantReplicas: 2},
+		{name: "Scale down postponed when cluster is not safe", stsReplicas: 3, podReplicas: 3, clusterSafe: false, wantReplicas: 3},
+		{name: "Scale down postponed until previous member leaves", stsReplicas: 3, podReplicas: 4, clusterSafe: true, wantReplicas: 3},
+		{name: "Scale down postponed when cluster safety cannot be checked", stsReplicas: 3, podReplicas: 3, checkFails: true, wantReplicas: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hazelcastv1alpha1.Hazelcast{
+				ObjectMeta: metav1.ObjectMeta{Name: "hazelcast", Namespace: "default"},
+				Spec:       hazelcastv1alpha1.HazelcastSpec{ClusterSize: &[]int32{1}[0]},
+			}
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: h.Name, Namespace: h.Namespace},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &tt.stsReplicas},
+				Status:     appsv1.StatefulSetStatus{Replicas: tt.podReplicas},
+			}
+			ts, err := fakeHttpServer(hazelcastUrl(h), func(writer http.ResponseWriter, request *http.Request) {
+				if tt.checkFails {
+					writer.WriteHeader(500)
+					return
+				}
+				writer.WriteHeader(200)
+				if tt.clusterSafe {
+					_, _ = writer.Write([]byte(`{"clusterSafe":true,"migrationQueueSize":0}`))
+				} else {
+					_, _ = writer.Write([]byte(`{"clusterSafe":false,"migrationQueueSize":12}`))
+				}
+			})
+			if err != nil {
+				t.Fatalf("Failed to start fake HTTP server: %v", err)
+			}
+			defer ts.Close()
+
+			rec := record.NewFakeRecorder(1)
+			r := HazelcastReconciler{Client: fakeClient(h, sts), Recorder: rec}
+			got, err := r.replicas(context.Background(), h, ctrl.Log)
+			if err != nil {
+				t.Fatalf("replicas() error = %v", err)
+			}
+			if got != tt.wantReplicas {
+				t.Errorf("replicas() = %d, want %d", got, tt.wantReplicas)
+			}
+			if tt.checkFails {
+				if len(rec.Events) != 1 {
+					t.Fatalf("Expected a warning event for the failed cluster safety check")
+				}
+				if msg := scalingMessage(h, got); !strings.Contains(msg, "postponed") {
+					t.Errorf("Expected the scaling message to report the failed check, got %s", msg)
+				}
+			}
+		})
+	}
+}
+
+func Test_scalingRetryAfter(t *testing.T) {
+	now := time.Now()
+	h := &hazelcastv1alpha1.Hazelcast{}
+	if d := scalingRetryAfter(h, now); d != retryAfter {
+		t.Errorf("scalingRetryAfter() = %v, want %v", d, retryAfter)
+	}
+
+	failingSince := func(d time.Duration) {
+		h.Status.Conditions = []metav1.Condition{{
+			Type:               hazelcastv1alpha1.ClusterSafe,
+			Status:             metav1.ConditionUnknown,
+			LastTransitionTime: metav1.NewTime(now.Add(-d)),
+		}}
+	}
+	failingSince(time.Second)
+	if d := scalingRetryAfter(h, now); d != retryAfter {
+		t.Errorf("scalingRetryAfter() = %v, want %v", d, retryAfter)
+	}
+	failingSince(time.Minute)
+	if d := scalingRetryAfter(h, now); d != time.Minute {
+		t.Errorf("scalingRetryAfter() = %v, want %v", d, time.Minute)
+	}
+	failingSince(time.Hour)
+	if d := scalingRetryAfter(h, now); d != maxScalingRetryAfter {
+		t.Errorf("scalingRetryAfter() = %v, want %v", d, maxScalingRetryAfter)
+	}
+}
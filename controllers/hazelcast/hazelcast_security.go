@@ -0,0 +1,241 @@
+package hazelcast
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/config"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
+)
+
+// credentials is the identity the operator uses to authenticate to the Hazelcast cluster.
+type credentials struct {
+	username string
+	password string
+}
+
+// connectionConfig contains the settings the operator needs to communicate with the Hazelcast members.
+type connectionConfig struct {
+	tls         *tls.Config
+	credentials *credentials
+	// checksum identifies the settings, the client is recreated when it changes
+	checksum string
+}
+
+func newConnectionConfig(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast) (connectionConfig, error) {
+	ts, err := tlsSecret(ctx, c, h)
+	if err != nil {
+		return connectionConfig{}, err
+	}
+	tlsCfg, err := tlsConfig(ts)
+	if err != nil {
+		return connectionConfig{}, err
+	}
+	creds, err := operatorCredentials(ctx, c, h)
+	if err != nil {
+		return connectionConfig{}, err
+	}
+
+	sum := sha256.New()
+	if ts != nil {
+		sum.Write([]byte(secretChecksum(ts)))
+	}
+	if creds != nil {
+		fmt.Fprintf(sum, "%s:%s", creds.username, creds.password)
+	}
+	return connectionConfig{tls: tlsCfg, credentials: creds, checksum: fmt.Sprintf("%x", sum.Sum(nil))}, nil
+}
+
+func operatorIdentitySecretName(h *hazelcastv1alpha1.Hazelcast) string {
+	return h.Name + n.OperatorIdentitySuffix
+}
+
+// operatorCredentials returns the credentials of the operator identity,
+// or nil when security is not enabled for the given Hazelcast resource.
+func operatorCredentials(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast) (*credentials, error) {
+	if !h.Spec.Security.IsEnabled() {
+		return nil, nil
+	}
+
+	s := &corev1.Secret{}
+	name := operatorIdentitySecretName(h)
+	err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: h.Namespace}, s)
+	if err != nil {
+		return nil, fmt.Errorf("could not get operator identity secret %s: %w", name, err)
+	}
+	return &credentials{
+		username: string(s.Data[n.SecurityUsernameKey]),
+		password: string(s.Data[n.SecurityPasswordKey]),
+	}, nil
+}
+
+func (r *HazelcastReconciler) reconcileOperatorIdentity(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) error {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operatorIdentitySecretName(h),
+			Namespace: h.Namespace,
+			Labels:    labels(h),
+		},
+	}
+
+	if !h.Spec.Security.IsEnabled() {
+		err := r.Client.Delete(ctx, s)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete operator identity secret: %w", err)
+		}
+		return nil
+	}
+
+	err := controllerutil.SetControllerReference(h, s, r.Scheme)
+	if err != nil {
+		return fmt.Errorf("failed to set owner reference on operator identity secret: %w", err)
+	}
+
+	opResult, err := util.CreateOrUpdate(ctx, r.Client, s, func() error {
+		// The credentials are generated only once, the members would reject new ones until they are restarted
+		if len(s.Data[n.SecurityUsernameKey]) != 0 && len(s.Data[n.SecurityPasswordKey]) != 0 {
+			return nil
+		}
+		s.Data = map[string][]byte{
+			n.SecurityUsernameKey: []byte(n.OperatorName),
+			n.SecurityPasswordKey: []byte(rand.String(32)),
+		}
+		return nil
+	})
+	if opResult != controllerutil.OperationResultNone {
+		logger.Info("Operation result", "Secret", s.Name, "result", opResult)
+	}
+	return err
+}
+
+// securityConfig returns the security section of the Hazelcast configuration.
+// The realm with the credentials is not part of it, it is read from the Secrets by securityRealms.
+func securityConfig(h *hazelcastv1alpha1.Hazelcast) config.Security {
+	var mapPermissions []config.Permission
+	for _, c := range h.Spec.Security.Clients {
+		for _, p := range c.MapPermissions {
+			actions := make([]string, len(p.Actions))
+			for j, a := range p.Actions {
+				actions[j] = string(a)
+			}
+			mapPermissions = append(mapPermissions, config.Permission{
+				Name:      p.Name,
+				Principal: c.Name,
+				Actions:   actions,
+			})
+		}
+	}
+
+	return config.Security{
+		Enabled: &[]bool{true}[0],
+		ClientAuthentication: config.ClientAuthentication{
+			Realm: n.SecurityRealm,
+		},
+		ClientPermissions: config.ClientPermissions{
+			All: &config.Permission{
+				Principal: n.OperatorIdentityName,
+			},
+			Map: mapPermissions,
+		},
+	}
+}
+
+// securityRealms returns the realm with the credentials of the operator identity and of the clients,
+// or nil when security is not enabled for the given Hazelcast resource.
+// It is written to the secret config, so that the credentials are neither stored in the ConfigMap nor passed on the command line.
+func securityRealms(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast) ([]config.Realm, error) {
+	if !h.Spec.Security.IsEnabled() {
+		return nil, nil
+	}
+
+	op, err := operatorCredentials(ctx, c, h)
+	if err != nil {
+		return nil, err
+	}
+	users := []config.User{
+		{
+			Username: op.username,
+			Password: op.password,
+			Roles:    []string{n.OperatorIdentityName},
+		},
+	}
+	for _, ci := range h.Spec.Security.Clients {
+		s := &corev1.Secret{}
+		err := c.Get(ctx, types.NamespacedName{Name: ci.SecretName, Namespace: h.Namespace}, s)
+		if err != nil {
+			return nil, fmt.Errorf("could not get the credentials of client %s from secret %s: %w", ci.Name, ci.SecretName, err)
+		}
+		if err = validateClientCredentials(s); err != nil {
+			return nil, fmt.Errorf("invalid credentials of client %s: %w", ci.Name, err)
+		}
+		users = append(users, config.User{
+			Username: string(s.Data[n.SecurityUsernameKey]),
+			Password: string(s.Data[n.SecurityPasswordKey]),
+			Roles:    []string{ci.Name},
+		})
+	}
+
+	return []config.Realm{
+		{
+			Name: n.SecurityRealm,
+			Authentication: config.Authentication{
+				Simple: config.SimpleAuthentication{Users: users},
+			},
+		},
+	}, nil
+}
+
+// validateClientCredentials checks that the secret holds a username and password identity.
+// Token identities cannot be verified by the simple realm of the members, so they are rejected rather than ignored.
+func validateClientCredentials(s *corev1.Secret) error {
+	if _, ok := s.Data[n.SecurityTokenKey]; ok {
+		return fmt.Errorf("secret %s contains a %q key, token identities are not supported", s.Name, n.SecurityTokenKey)
+	}
+	if len(s.Data[n.SecurityUsernameKey]) == 0 || len(s.Data[n.SecurityPasswordKey]) == 0 {
+		return fmt.Errorf("secret %s must contain %q and %q keys", s.Name, n.SecurityUsernameKey, n.SecurityPasswordKey)
+	}
+	return nil
+}
+
+// secretRefs returns the names of the Secrets the configuration of the members is read from,
+// so that the members are updated when they change.
+func secretRefs(h *hazelcastv1alpha1.Hazelcast) []string {
+	var names []string
+	if h.Spec.TLS.IsEnabled() {
+		names = append(names, h.Spec.TLS.SecretName)
+	}
+	if h.Spec.Security.IsEnabled() {
+		for _, c := range h.Spec.Security.Clients {
+			names = append(names, c.SecretName)
+		}
+	}
+	return names
+}
+
+// securityChecksum returns a checksum of the credentials, so that the members are restarted when they are rotated
+// or read from another Secret. The members read the credentials only on startup.
+func securityChecksum(realms []config.Realm) (string, error) {
+	if len(realms) == 0 {
+		return "", nil
+	}
+	yml, err := yaml.Marshal(realms)
+	if err != nil {
+		return "", err
+	}
+	// A cryptographic hash is used since the checksum is visible on the pods
+	return fmt.Sprintf("%x", sha256.Sum256(yml)), nil
+}
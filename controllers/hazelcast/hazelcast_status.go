@@ -7,12 +7,14 @@ import (
 	"time"
 
 	hztypes "github.com/hazelcast/hazelcast-go-client/types"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/metrics"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
 )
 
@@ -40,6 +42,20 @@ func pendingPhase(retryAfter time.Duration) optionsBuilder {
 	}
 }
 
+func scalingPhase(retryAfter time.Duration) optionsBuilder {
+	return optionsBuilder{
+		phase:      hazelcastv1alpha1.Scaling,
+		retryAfter: retryAfter,
+	}
+}
+
+func upgradingPhase(retryAfter time.Duration) optionsBuilder {
+	return optionsBuilder{
+		phase:      hazelcastv1alpha1.Upgrading,
+		retryAfter: retryAfter,
+	}
+}
+
 func runningPhase() optionsBuilder {
 	return optionsBuilder{
 		phase: hazelcastv1alpha1.Running,
@@ -119,15 +135,38 @@ func updateFailedMember(h *hazelcastv1alpha1.Hazelcast, err *util.PodError) {
 	})
 }
 
-// update takes the options provided by the given optionsBuilder, applies them all and then updates the Hazelcast resource
-func update(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast, options optionsBuilder) (ctrl.Result, error) {
+// readyMembers returns the members reported by the options, or the members seen by the last status polling of the client
+// for the phases which do not report them, e.g. Scaling or Pending, so that the ready members are kept during these phases.
+func readyMembers(options optionsBuilder, cl *Client) map[hztypes.UUID]*MemberData {
+	if options.readyMembers != nil {
+		return options.readyMembers
+	}
+	cl.Lock()
+	defer cl.Unlock()
+	if cl.Status == nil {
+		return nil
+	}
+	return cl.Status.MemberMap
+}
+
+// update takes the options provided by the given optionsBuilder, applies them all and then updates the Hazelcast resource.
+// An event is recorded when the phase of the cluster changes.
+func (r *HazelcastReconciler) update(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, options optionsBuilder) (ctrl.Result, error) {
+	previousPhase := h.Status.Phase
 	h.Status.Phase = options.phase
 	h.Status.Cluster.ReadyMembers = "N/A"
+	h.Status.ClusterSize = 0
+	h.Status.Selector = k8slabels.SelectorFromSet(labels(h)).String()
 
 	cl, ok := GetClient(types.NamespacedName{Name: h.Name, Namespace: h.Namespace})
 
 	if ok && cl.IsClientConnected() {
+		options.readyMembers = readyMembers(options, cl)
 		h.Status.Cluster.ReadyMembers = fmt.Sprintf("%d/%d", len(options.readyMembers), *h.Spec.ClusterSize)
+		h.Status.ClusterSize = int32(len(options.readyMembers))
+		setConditionTrue(h, hazelcastv1alpha1.ClientConnected, reasonConnected, "")
+	} else {
+		setConditionFalse(h, hazelcastv1alpha1.ClientConnected, reasonNotConnected, "Operator is not connected to the cluster")
 	}
 
 	h.Status.Message = options.message
@@ -146,19 +185,42 @@ func update(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast
 			RemainingDataLoadTime:   options.restoreState.remainingDataLoadTimeSec(),
 			RemainingValidationTime: options.restoreState.remainingValidationTimeSec(),
 		}
+		setRestoreCompleteCondition(h, rs)
 	}
-	if err := c.Status().Update(ctx, h); err != nil {
+	if err := r.Client.Status().Update(ctx, h); err != nil {
 		// Conflicts are expected and will be handled on the next reconcile loop, no need to error out here
 		if errors.IsConflict(err) {
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
+	metrics.SetClusterStatus(h)
+	if previousPhase != options.phase {
+		r.recordPhaseChange(h, previousPhase, options)
+	}
 	if options.phase == hazelcastv1alpha1.Failed {
 		return ctrl.Result{}, options.err
 	}
-	if options.phase == hazelcastv1alpha1.Pending {
+	if options.phase == hazelcastv1alpha1.Pending || options.phase == hazelcastv1alpha1.Scaling || options.phase == hazelcastv1alpha1.Upgrading {
 		return ctrl.Result{Requeue: true, RequeueAfter: options.retryAfter}, nil
 	}
 	return ctrl.Result{}, nil
 }
+
+func (r *HazelcastReconciler) recordPhaseChange(h *hazelcastv1alpha1.Hazelcast, previous hazelcastv1alpha1.Phase, options optionsBuilder) {
+	if previous == "" {
+		previous = "None"
+	}
+	msg := fmt.Sprintf("Phase changed from %s to %s", previous, options.phase)
+	if options.message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, options.message)
+	}
+	if options.phase == hazelcastv1alpha1.Failed {
+		if options.err != nil && options.message == "" {
+			msg = fmt.Sprintf("%s: %s", msg, options.err)
+		}
+		r.Recorder.Event(h, corev1.EventTypeWarning, reasonPhaseChanged, msg)
+		return
+	}
+	r.Recorder.Event(h, corev1.EventTypeNormal, reasonPhaseChanged, msg)
+}
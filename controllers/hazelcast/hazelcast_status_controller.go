@@ -18,6 +18,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/metrics"
+	codecTypes "github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
 )
 
 type Client struct {
@@ -30,6 +32,7 @@ type Client struct {
 	Status               *Status
 	triggerReconcileChan chan event.GenericEvent
 	statusTicker         *StatusTicker
+	connChecksum         string
 }
 
 func (cl *Client) IsClientConnected() bool {
@@ -63,6 +66,8 @@ func (cl *Client) AreAllMembersAccessible() bool {
 type Status struct {
 	MemberMap               map[hztypes.UUID]*MemberData
 	ClusterHotRestartStatus ClusterHotRestartStatus
+	// Statistics of the maps aggregated across the members by map name
+	MapStats map[string]LocalMapStats
 }
 
 type StatusTicker struct {
@@ -115,13 +120,18 @@ func GetClient(ns types.NamespacedName) (client *Client, ok bool) {
 	return nil, false
 }
 
-func CreateClient(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, channel chan event.GenericEvent, l logr.Logger) {
+func CreateClient(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, conn connectionConfig, channel chan event.GenericEvent, l logr.Logger) {
 	ns := types.NamespacedName{Name: h.Name, Namespace: h.Namespace}
-	if _, ok := clients.Load(ns); ok {
-		return
+	if c, ok := clients.Load(ns); ok {
+		if c.(*Client).connChecksum == conn.checksum {
+			return
+		}
+		// The existing connection uses the previous TLS or security settings, it is recreated once with the current ones
+		ShutdownClient(ctx, ns)
 	}
-	config := buildConfig(h)
+	config := buildConfig(h, conn)
 	c := newHazelcastClient(l, ns, channel)
+	c.connChecksum = conn.checksum
 	c.start(ctx, config)
 	clients.Store(ns, c)
 }
@@ -195,6 +205,7 @@ func (c *Client) shutdown(ctx context.Context) {
 	c.Lock()
 	defer c.Unlock()
 
+	metrics.SetMemberPartitions(c.NamespacedName, nil)
 	if c.client == nil {
 		return
 	}
@@ -239,6 +250,7 @@ func (c *Client) updateMembers(ctx context.Context) {
 	activeMemberList := hzInternalClient.OrderedMembers()
 	activeMembers := make(map[hztypes.UUID]*MemberData, len(activeMemberList))
 	newClusterHotRestartStatus := &ClusterHotRestartStatus{}
+	mapStats := make(map[string]LocalMapStats)
 
 	for _, memberInfo := range activeMemberList {
 		activeMembers[memberInfo.UUID] = newMemberData(memberInfo)
@@ -247,14 +259,102 @@ func (c *Client) updateMembers(ctx context.Context) {
 			activeMembers[memberInfo.UUID].enrichMemberData(state.TimedMemberState)
 			newClusterHotRestartStatus = &state.TimedMemberState.MemberState.ClusterHotRestartStatus
 		}
+		addMapStats(mapStats, c.getMapStats(ctx, memberInfo.UUID))
 	}
 
 	c.Lock()
+	metrics.SetMemberPartitions(c.NamespacedName, ownedPartitions(activeMembers))
 	c.Status.MemberMap = activeMembers
 	c.Status.ClusterHotRestartStatus = *newClusterHotRestartStatus
+	c.Status.MapStats = mapStats
 	c.Unlock()
 }
 
+// getMapStats returns the statistics of the maps on the member, read from the latest metrics collected by the member.
+// The timed member state only lists the names of the maps with statistics.
+func (c *Client) getMapStats(ctx context.Context, uuid hztypes.UUID) map[string]LocalMapStats {
+	ci := hazelcast.NewClientInternal(c.client)
+	resp, err := ci.InvokeOnMember(ctx, codec.EncodeMCReadMetricsRequest(uuid, 0), uuid, nil)
+	if err != nil {
+		c.Log.Error(err, "Reading the metrics failed.", "CR", c.NamespacedName)
+		return nil
+	}
+	blobs, _ := codec.DecodeMCReadMetricsResponse(resp)
+	if len(blobs) == 0 {
+		return nil
+	}
+	latest := blobs[0]
+	for _, b := range blobs[1:] {
+		if b.Timestamp > latest.Timestamp {
+			latest = b
+		}
+	}
+	ms, err := codec.DecodeMetricsBlob(latest.Blob)
+	if err != nil {
+		c.Log.Error(err, "Decoding the metrics failed.", "CR", c.NamespacedName)
+		return nil
+	}
+	return localMapStats(ms)
+}
+
+// localMapStats returns the statistics of the maps from the metrics of a member.
+func localMapStats(ms []codecTypes.Metric) map[string]LocalMapStats {
+	stats := map[string]LocalMapStats{}
+	for _, m := range ms {
+		if m.Prefix != "map" || m.Discriminator != "name" {
+			continue
+		}
+		s := stats[m.DiscriminatorValue]
+		switch m.Name {
+		case "ownedEntryCount":
+			s.OwnedEntryCount = m.Value
+		case "backupEntryCount":
+			s.BackupEntryCount = m.Value
+		case "ownedEntryMemoryCost":
+			s.OwnedEntryMemoryCost = m.Value
+		case "hits":
+			s.Hits = m.Value
+		case "lastUpdateTime":
+			s.LastUpdateTime = m.Value
+		default:
+			continue
+		}
+		stats[m.DiscriminatorValue] = s
+	}
+	return stats
+}
+
+// addMapStats adds the map statistics of a member to the statistics aggregated across the members.
+func addMapStats(total map[string]LocalMapStats, member map[string]LocalMapStats) {
+	for name, ms := range member {
+		t := total[name]
+		t.OwnedEntryCount += ms.OwnedEntryCount
+		t.BackupEntryCount += ms.BackupEntryCount
+		t.OwnedEntryMemoryCost += ms.OwnedEntryMemoryCost
+		t.Hits += ms.Hits
+		if ms.LastUpdateTime > t.LastUpdateTime {
+			t.LastUpdateTime = ms.LastUpdateTime
+		}
+		total[name] = t
+	}
+}
+
+func memberIDs(members map[hztypes.UUID]*MemberData) []string {
+	ids := make([]string, 0, len(members))
+	for uuid := range members {
+		ids = append(ids, uuid.String())
+	}
+	return ids
+}
+
+func ownedPartitions(members map[hztypes.UUID]*MemberData) map[string]int32 {
+	partitions := make(map[string]int32, len(members))
+	for uuid, m := range members {
+		partitions[uuid.String()] = m.Partitions
+	}
+	return partitions
+}
+
 func fetchTimedMemberState(ctx context.Context, client *hazelcast.Client, uuid hztypes.UUID) (string, error) {
 	ci := hazelcast.NewClientInternal(client)
 	req := codec.EncodeMCGetTimedMemberStateRequest()
@@ -284,6 +384,15 @@ type MemberState struct {
 	ClusterHotRestartStatus ClusterHotRestartStatus `json:"clusterHotRestartStatus"`
 }
 
+// LocalMapStats are the statistics of a map on a member, only the maps with statistics enabled are reported.
+type LocalMapStats struct {
+	OwnedEntryCount      int64
+	BackupEntryCount     int64
+	OwnedEntryMemoryCost int64
+	Hits                 int64
+	LastUpdateTime       int64
+}
+
 type NodeState struct {
 	State         string `json:"nodeState"`
 	MemberVersion string `json:"memberVersion"`
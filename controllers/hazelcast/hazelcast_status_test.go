@@ -0,0 +1,168 @@
+package hazelcast
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	hztypes "github.com/hazelcast/hazelcast-go-client/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
+	codecTypes "github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
+)
+
+func Test_updateRecordsPhaseChange(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "hazelcast",
+			Namespace:  "default",
+			Generation: 2,
+		},
+		Spec: hazelcastv1alpha1.HazelcastSpec{
+			ClusterSize: &[]int32{3}[0],
+		},
+		Status: hazelcastv1alpha1.HazelcastStatus{
+			Phase: hazelcastv1alpha1.Running,
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := HazelcastReconciler{Client: fakeClient(h), Recorder: recorder}
+
+	setConditionFalse(h, hazelcastv1alpha1.StatefulSetReady, reasonMembersNotReady, "")
+	_, err := r.update(context.Background(), h, pendingPhase(retryAfter))
+	if err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("Expected one event, got %d", len(recorder.Events))
+	}
+	if e := <-recorder.Events; !strings.Contains(e, "from Running to Pending") {
+		t.Errorf("Unexpected event: %s", e)
+	}
+	c := meta.FindStatusCondition(h.Status.Conditions, hazelcastv1alpha1.StatefulSetReady)
+	if c == nil || c.ObservedGeneration != h.Generation {
+		t.Errorf("Expected StatefulSetReady condition with observed generation %d, got %v", h.Generation, c)
+	}
+
+	_, err = r.update(context.Background(), h, pendingPhase(retryAfter))
+	if err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Expected no event when the phase does not change, got %d", len(recorder.Events))
+	}
+}
+
+func Test_addMapStats(t *testing.T) {
+	mapMetric := func(name, metric string, value int64) codecTypes.Metric {
+		return codecTypes.Metric{Prefix: "map", Name: metric, Discriminator: "name", DiscriminatorValue: name, Value: value}
+	}
+	members := [][]codecTypes.Metric{
+		{
+			mapMetric("orders", "ownedEntryCount", 10),
+			mapMetric("orders", "backupEntryCount", 12),
+			mapMetric("orders", "ownedEntryMemoryCost", 1024),
+			mapMetric("orders", "hits", 3),
+			mapMetric("orders", "lastUpdateTime", 1650000000000),
+			{Prefix: "os", Name: "processCpuLoad", Value: 1},
+		},
+		{
+			mapMetric("orders", "ownedEntryCount", 12),
+			mapMetric("orders", "backupEntryCount", 10),
+			mapMetric("orders", "ownedEntryMemoryCost", 2048),
+			mapMetric("orders", "hits", 4),
+			mapMetric("orders", "lastUpdateTime", 1650000005000),
+			mapMetric("orders", "getCount", 100),
+			mapMetric("users", "ownedEntryCount", 1),
+		},
+	}
+	total := map[string]LocalMapStats{}
+	for _, ms := range members {
+		addMapStats(total, localMapStats(ms))
+	}
+
+	want := LocalMapStats{OwnedEntryCount: 22, BackupEntryCount: 22, OwnedEntryMemoryCost: 3072, Hits: 7, LastUpdateTime: 1650000005000}
+	if total["orders"] != want {
+		t.Errorf("Expected %+v, got %+v", want, total["orders"])
+	}
+	if total["users"].OwnedEntryCount != 1 {
+		t.Errorf("Unexpected stats of map users: %+v", total["users"])
+	}
+}
+
+func Test_configConflictsKeptInRunningPhase(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hazelcast",
+			Namespace: "default",
+		},
+		Spec: hazelcastv1alpha1.HazelcastSpec{
+			ClusterSize:        &[]int32{3}[0],
+			CustomConfigMapRef: "custom-config",
+		},
+		Status: hazelcastv1alpha1.HazelcastStatus{
+			ConfigConflicts: []string{"hazelcast.cluster-name"},
+		},
+	}
+	custom := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "custom-config",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			n.HazelcastConfigKey: "hazelcast:\n  network:\n    rest-api:\n      enabled: false\n",
+		},
+	}
+	c := fakeClient(h, custom)
+	r := HazelcastReconciler{Client: c, Scheme: c.Scheme(), Recorder: record.NewFakeRecorder(10)}
+	ctx := context.Background()
+
+	if err := r.reconcileConfigMap(ctx, h, ctrl.Log); err != nil {
+		t.Fatalf("reconcileConfigMap() error = %v", err)
+	}
+	if err := r.updateLastSuccessfulConfiguration(ctx, h, ctrl.Log); err != nil {
+		t.Fatalf("updateLastSuccessfulConfiguration() error = %v", err)
+	}
+	if _, err := r.update(ctx, h, runningPhase()); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+
+	fetched := &hazelcastv1alpha1.Hazelcast{}
+	if err := c.Get(ctx, types.NamespacedName{Name: h.Name, Namespace: h.Namespace}, fetched); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"hazelcast.network.rest-api.enabled"}
+	if !reflect.DeepEqual(fetched.Status.ConfigConflicts, want) {
+		t.Errorf("Expected config conflicts %v, got %v", want, fetched.Status.ConfigConflicts)
+	}
+	if _, ok := fetched.Annotations[n.LastSuccessfulSpecAnnotation]; !ok {
+		t.Errorf("Expected the last successful spec to be saved")
+	}
+}
+
+func Test_readyMembersKeptWhenPhaseDoesNotReportThem(t *testing.T) {
+	members := map[hztypes.UUID]*MemberData{
+		hztypes.NewUUID(): {Address: "10.0.0.1:5701"},
+		hztypes.NewUUID(): {Address: "10.0.0.2:5701"},
+		hztypes.NewUUID(): {Address: "10.0.0.3:5701"},
+	}
+	cl := &Client{Status: &Status{MemberMap: members}}
+
+	for _, o := range []optionsBuilder{scalingPhase(retryAfter), pendingPhase(retryAfter), failedPhase(nil)} {
+		if got := readyMembers(o, cl); len(got) != 3 {
+			t.Errorf("Expected the %s phase to keep the 3 ready members of the client, got %d", o.phase, len(got))
+		}
+	}
+	running := runningPhase().withStatus(&Status{MemberMap: map[hztypes.UUID]*MemberData{hztypes.NewUUID(): {}}})
+	if got := readyMembers(running, cl); len(got) != 1 {
+		t.Errorf("Expected the members reported by the phase, got %d", len(got))
+	}
+}
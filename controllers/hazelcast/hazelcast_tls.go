@@ -0,0 +1,99 @@
+package hazelcast
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
+)
+
+// tlsSecret returns the Secret with the TLS certificates, or nil when TLS is not enabled for the given Hazelcast resource.
+func tlsSecret(ctx context.Context, c client.Client, h *hazelcastv1alpha1.Hazelcast) (*corev1.Secret, error) {
+	if !h.Spec.TLS.IsEnabled() {
+		return nil, nil
+	}
+
+	s := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Name: h.Spec.TLS.SecretName, Namespace: h.Namespace}, s)
+	if err != nil {
+		return nil, fmt.Errorf("could not get TLS secret %s: %w", h.Spec.TLS.SecretName, err)
+	}
+	return s, nil
+}
+
+// tlsConfig returns the TLS configuration used by the operator to connect to the Hazelcast members,
+// or nil when there is no TLS secret.
+func tlsConfig(s *corev1.Secret) (*tls.Config, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(s.Data[n.TLSCAKey]) {
+		return nil, fmt.Errorf("TLS secret %s does not contain a valid CA certificate under %s key", s.Name, n.TLSCAKey)
+	}
+	cert, err := tls.X509KeyPair(s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("TLS secret %s does not contain a valid key pair: %w", s.Name, err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+		// Members are accessed by their pod IPs that are not known when the certificate is issued,
+		// therefore the hostname check is skipped and only the certificate chain is verified.
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verifyCertificateChain(pool),
+	}, nil
+}
+
+func verifyCertificateChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no certificate presented by the member")
+		}
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+}
+
+// secretChecksum returns a checksum of the data of the Secret, so that a rotation within the same Secret is detected.
+// A cryptographic hash is used since the checksum is visible on the pods.
+func secretChecksum(s *corev1.Secret) string {
+	keys := make([]string, 0, len(s.Data))
+	for k := range s.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sum := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(sum, "%s=%x;", k, s.Data[k])
+	}
+	return fmt.Sprintf("%x", sum.Sum(nil))
+}
@@ -0,0 +1,104 @@
+package hazelcast
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
+)
+
+// isVersionUpdated returns true if the Hazelcast version differs from the last successfully applied one.
+func isVersionUpdated(h *hazelcastv1alpha1.Hazelcast) bool {
+	lastSpec, ok := h.LastSuccessfulSpec()
+	return ok && lastSpec.Version != h.Spec.Version
+}
+
+// updatePartition returns the rolling update partition of the StatefulSet, the pods with a lower ordinal keep the
+// previous version. While the version is upgraded, the partition is decreased one by one and only when the cluster is safe,
+// so that only a single member runs with the data that is not migrated yet. It also returns whether the upgrade is in progress.
+func (r *HazelcastReconciler) updatePartition(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, replicas int32, logger logr.Logger) (int32, bool, error) {
+	if !isVersionUpdated(h) {
+		return 0, false, nil
+	}
+
+	sts := &appsv1.StatefulSet{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: h.Name, Namespace: h.Namespace}, sts)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	partition := replicas
+	updated := int32(0)
+	if sts.Spec.Template.Spec.Containers[0].Image == h.DockerImage() {
+		updated = sts.Status.UpdatedReplicas
+		partition = 0
+		if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil && *ru.Partition < replicas {
+			partition = *ru.Partition
+		}
+	}
+	if partition == 0 {
+		return 0, updated < replicas, nil
+	}
+
+	if sts.Status.ObservedGeneration != sts.Generation || updated < replicas-partition || sts.Status.ReadyReplicas < replicas {
+		logger.Info("Waiting for the upgraded member to join the cluster", "partition", partition)
+		return partition, true, nil
+	}
+
+	conn, err := newConnectionConfig(ctx, r.Client, h)
+	if err != nil {
+		return 0, false, err
+	}
+	safe, err := NewRestClient(h, conn).IsClusterSafe(ctx)
+	setClusterSafeCondition(h, safe, err)
+	if err != nil {
+		logger.Info("Could not check if the cluster is safe, postponing the upgrade", "error", err.Error())
+		return partition, true, nil
+	}
+	if !safe {
+		logger.Info("Cluster is not safe, postponing the upgrade", "partition", partition)
+		return partition, true, nil
+	}
+	return partition - 1, true, nil
+}
+
+// ensureClusterVersion upgrades the cluster version once all the members run the new minor version.
+func (r *HazelcastReconciler) ensureClusterVersion(ctx context.Context, h *hazelcastv1alpha1.Hazelcast, logger logr.Logger) error {
+	if !isVersionUpdated(h) {
+		return nil
+	}
+	target, ok := util.ParseVersion(h.Spec.Version)
+	if !ok {
+		return nil
+	}
+
+	conn, err := newConnectionConfig(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+	rest := NewRestClient(h, conn)
+	clusterVersion, err := rest.GetClusterVersion(ctx)
+	if err != nil {
+		return err
+	}
+	current, ok := util.ParseVersion(clusterVersion)
+	if !ok || current.Compare(target) >= 0 || current.ClusterVersion() == target.ClusterVersion() {
+		return nil
+	}
+
+	logger.Info("Upgrading the cluster version", "from", current.ClusterVersion(), "to", target.ClusterVersion())
+	return rest.ChangeClusterVersion(ctx, target.ClusterVersion())
+}
+
+func upgradeMessage(h *hazelcastv1alpha1.Hazelcast, partition int32) string {
+	return fmt.Sprintf("Upgrading the members to version %s, %d members remaining", h.Spec.Version, partition)
+}
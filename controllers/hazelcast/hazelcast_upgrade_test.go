@@ -0,0 +1,77 @@
+package hazelcast
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
+)
+
+func Test_updatePartitionDecreasedOneByOneWhenClusterIsSafe(t *testing.T) {
+	tests := []struct {
+		name          string
+		image         string
+		partition     int32
+		updated       int32
+		wantPartition int32
+		wantUpgrading bool
+	}{
+		{name: "Upgrade starts with the last member", image: "hazelcast/hazelcast:5.1.2", wantPartition: 2, wantUpgrading: true},
+		{name: "Next member upgraded when previous one joined", image: "hazelcast/hazelcast:5.1.3", partition: 2, updated: 1, wantPartition: 1, wantUpgrading: true},
+		{name: "Waiting for upgraded member", image: "hazelcast/hazelcast:5.1.3", partition: 2, updated: 0, wantPartition: 2, wantUpgrading: true},
+		{name: "Upgrade finished", image: "hazelcast/hazelcast:5.1.3", partition: 0, updated: 3, wantPartition: 0, wantUpgrading: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hazelcastv1alpha1.Hazelcast{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "hazelcast",
+					Namespace:   "default",
+					Annotations: map[string]string{n.LastSuccessfulSpecAnnotation: `{"version":"5.1.2"}`},
+				},
+				Spec: hazelcastv1alpha1.HazelcastSpec{
+					ClusterSize: &[]int32{3}[0],
+					Repository:  "hazelcast/hazelcast",
+					Version:     "5.1.3",
+				},
+			}
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: h.Name, Namespace: h.Namespace},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: h.Spec.ClusterSize,
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &tt.partition},
+					},
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: tt.image}}},
+					},
+				},
+				Status: appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: tt.updated},
+			}
+			ts, err := fakeHttpServer(hazelcastUrl(h), func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(200)
+				_, _ = writer.Write([]byte(`{"clusterSafe":true,"migrationQueueSize":0}`))
+			})
+			if err != nil {
+				t.Fatalf("Failed to start fake HTTP server: %v", err)
+			}
+			defer ts.Close()
+
+			r := HazelcastReconciler{Client: fakeClient(h, sts)}
+			partition, upgrading, err := r.updatePartition(context.Background(), h, 3, ctrl.Log)
+			if err != nil {
+				t.Fatalf("updatePartition() error = %v", err)
+			}
+			if partition != tt.wantPartition || upgrading != tt.wantUpgrading {
+				t.Errorf("updatePartition() = %d, %v, want %d, %v", partition, upgrading, tt.wantPartition, tt.wantUpgrading)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/metrics"
 	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
 )
@@ -103,7 +104,11 @@ func (r *HotBackupReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 	if h.Status.Phase != hazelcastv1alpha1.Running {
 		return updateHotBackupStatus(ctx, r.Client, hb, failedHbStatus(apiErrors.NewServiceUnavailable("Hazelcast CR is not ready")))
 	}
-	rest := NewRestClient(h)
+	conn, err := newConnectionConfig(ctx, r.Client, h)
+	if err != nil {
+		return updateHotBackupStatus(ctx, r.Client, hb, failedHbStatus(err))
+	}
+	rest := NewRestClient(h, conn)
 
 	if hb.Spec.Schedule != "" {
 		entry, err := r.cron.AddFunc(hb.Spec.Schedule, func() {
@@ -146,6 +151,7 @@ func (r *HotBackupReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 		agentRest := NewAgentRestClient(h, hb, agentAddresses)
 		err = r.triggerUploadBackup(ctx, hb, agentRest, logger)
 		if err != nil {
+			metrics.IncHotBackupFailures(hb)
 			return updateHotBackupStatus(ctx, r.Client, hb, failedHbStatus(fmt.Errorf("error while uploading the backup: %w", err)))
 		}
 	}
@@ -208,6 +214,9 @@ func (r *HotBackupReconciler) updateHotBackupStatus(hzClient *Client, ctx contex
 	}
 	if currentState.IsFinished() {
 		r.Log.Info("HotBackup task finished.", "state", currentState)
+		if currentState == hazelcastv1alpha1.HotBackupFailure {
+			metrics.IncHotBackupFailures(hb)
+		}
 		if s, ok := r.statuses.LoadAndDelete(namespacedName); ok {
 			s.(*StatusTicker).stop()
 		}
@@ -285,8 +294,10 @@ func (r *HotBackupReconciler) triggerHotBackup(ctx context.Context, req reconcil
 		_, _ = updateHotBackupStatus(ctx, r.Client, hb, pendingHbStatus())
 	}
 
+	metrics.IncHotBackups(hb)
 	err = rest.ChangeState(ctx, Passive)
 	if err != nil {
+		metrics.IncHotBackupFailures(hb)
 		return fmt.Errorf("error creating HotBackup. Could not change the cluster state to PASSIVE: %w", err)
 	}
 	defer func(rest *RestClient) {
@@ -297,6 +308,7 @@ func (r *HotBackupReconciler) triggerHotBackup(ctx context.Context, req reconcil
 	}(rest)
 	err = rest.HotBackup(ctx)
 	if err != nil {
+		metrics.IncHotBackupFailures(hb)
 		return fmt.Errorf("error creating HotBackup: %w", err)
 	}
 	return nil
@@ -5,23 +5,35 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/hazelcast/hazelcast-go-client"
 	proto "github.com/hazelcast/hazelcast-go-client"
+	hztypes "github.com/hazelcast/hazelcast-go-client/types"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/controllers/hazelcast/validation"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/config"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/metrics"
 	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/protocol/codec"
 	codecTypes "github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
@@ -35,7 +47,10 @@ type MapReconciler struct {
 	Scheme *runtime.Scheme
 }
 
-const retryAfterForMap = 5 * time.Second
+const (
+	retryAfterForMap            = 5 * time.Second
+	maxRetryAfterForMapDeletion = time.Minute
+)
 
 //+kubebuilder:rbac:groups=hazelcast.com,resources=maps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=hazelcast.com,resources=maps/status,verbs=get;update;patch
@@ -54,6 +69,16 @@ func (r *MapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, fmt.Errorf("failed to get Map: %w", err)
 	}
 
+	err = r.addFinalizer(ctx, m, logger)
+	if err != nil {
+		return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()))
+	}
+
+	//Check if the Map CR is marked to be deleted
+	if m.GetDeletionTimestamp() != nil {
+		return r.executeFinalizer(ctx, m, logger)
+	}
+
 	h := &hazelcastv1alpha1.Hazelcast{}
 	err = r.Client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: m.Spec.HazelcastResourceName}, h)
 	if err != nil {
@@ -70,7 +95,18 @@ func (r *MapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()))
 	}
 
+	err = ValidateMapSpec(&m.Spec, h)
+	if err != nil {
+		return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()))
+	}
+
+	err = r.validateWanReplicationRef(ctx, &m.Spec, h)
+	if err != nil {
+		return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()))
+	}
+
 	s, createdBefore := m.ObjectMeta.Annotations[n.LastSuccessfulSpecAnnotation]
+	var addedIndexes []hazelcastv1alpha1.IndexConfig
 
 	if createdBefore {
 		ms, err := json.Marshal(m.Spec)
@@ -80,8 +116,26 @@ func (r *MapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()))
 		}
 		if s == string(ms) {
-			logger.Info("Map Config was already applied.", "name", m.Name, "namespace", m.Namespace)
-			return updateMapStatus(ctx, r.Client, m, successStatus())
+			var joined []string
+			previous := m.Status.MemberStatuses
+			m.Status.MemberStatuses, joined = activeMemberStatuses(m)
+			metrics.DeleteMapConfigFailures(m, leftMembers(previous, m.Status.MemberStatuses, joined))
+			if len(joined) != 0 {
+				joined, err = membersLackingMap(ctx, m, joined)
+				if err != nil {
+					return updateMapStatus(ctx, r.Client, m, successStatus().
+						withMessage(fmt.Sprintf("Could not check the map config of the members: %s", err.Error())).
+						withMemberStatuses(m.Status.MemberStatuses).
+						withRetryAfter(retryAfterForMap))
+				}
+			}
+			if len(joined) == 0 {
+				logger.Info("Map Config was already applied.", "name", m.Name, "namespace", m.Namespace)
+				return r.detectDrift(ctx, m, logger)
+			}
+			// The members that joined the cluster without the map, e.g. after a full restart, get its config
+			logger.Info("Applying Map Config to the members that joined the cluster.", "members", joined)
+			return r.applyMapConfig(ctx, m, false, runtimeAddedIndexes(m), logger)
 		}
 		lastSpec := &hazelcastv1alpha1.MapSpec{}
 		err = json.Unmarshal([]byte(s), lastSpec)
@@ -94,8 +148,16 @@ func (r *MapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		if err != nil {
 			return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()))
 		}
+		addedIndexes, _ = util.AddedIndexConfigs(m.Spec.Indexes, lastSpec.Indexes)
 	}
 
+	// The members applied the previous spec, so the current one is sent to all of them
+	m.Status.MemberStatuses = nil
+	return r.applyMapConfig(ctx, m, createdBefore, addedIndexes, logger)
+}
+
+// applyMapConfig sends the map config to the members which have not applied it yet and waits until it is persisted.
+func (r *MapReconciler) applyMapConfig(ctx context.Context, m *hazelcastv1alpha1.Map, createdBefore bool, addedIndexes []hazelcastv1alpha1.IndexConfig, logger logr.Logger) (ctrl.Result, error) {
 	cl, err := GetHazelcastClient(m)
 	if err != nil {
 		if errors.IsInternalError(err) {
@@ -106,7 +168,9 @@ func (r *MapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			withMessage(err.Error()))
 	}
 
-	requeue, err := updateMapStatus(ctx, r.Client, m, pendingStatus(0).withMessage("Applying new map configuration."))
+	requeue, err := updateMapStatus(ctx, r.Client, m, pendingStatus(0).
+		withMessage("Applying new map configuration.").
+		withMemberStatuses(m.Status.MemberStatuses))
 	if err != nil {
 		return requeue, err
 	}
@@ -119,18 +183,37 @@ func (r *MapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			withMemberStatuses(ms))
 	}
 
-	requeue, err = updateMapStatus(ctx, r.Client, m, persistingStatus(1*time.Second).withMessage("Persisting the applied map config."))
+	err = addIndexes(ctx, m, cl, addedIndexes)
+	if err != nil {
+		return updateMapStatus(ctx, r.Client, m, pendingStatus(retryAfterForMap).
+			withError(err).
+			withMessage(err.Error()).
+			withMemberStatuses(ms))
+	}
+
+	err = checkMapInitialization(ctx, m, cl)
+	if err != nil {
+		return updateMapStatus(ctx, r.Client, m, failedStatus(err).
+			withMessage(err.Error()).
+			withMemberStatuses(ms))
+	}
+
+	requeue, err = updateMapStatus(ctx, r.Client, m, persistingStatus(1*time.Second).
+		withMessage("Persisting the applied map config.").
+		withMemberStatuses(ms))
 	if err != nil {
 		return requeue, err
 	}
 
 	persisted, err := r.validateMapConfigPersistence(ctx, m)
 	if err != nil {
-		return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()))
+		return updateMapStatus(ctx, r.Client, m, failedStatus(err).withMessage(err.Error()).withMemberStatuses(ms))
 	}
 
 	if !persisted {
-		return updateMapStatus(ctx, r.Client, m, persistingStatus(1*time.Second).withMessage("Waiting for Map Config to be persisted."))
+		return updateMapStatus(ctx, r.Client, m, persistingStatus(1*time.Second).
+			withMessage("Waiting for Map Config to be persisted.").
+			withMemberStatuses(ms))
 	}
 
 	err = r.updateLastSuccessfulConfiguration(ctx, m)
@@ -139,7 +222,155 @@ func (r *MapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	return updateMapStatus(ctx, r.Client, m, successStatus().
-		withMemberStatuses(nil))
+		withMemberStatuses(ms).
+		withAddedIndexes(addedIndexKeys(m.Status.AddedIndexes, addedIndexes)).
+		withRetryAfter(refreshInterval(m, time.Now())))
+}
+
+func (r *MapReconciler) addFinalizer(ctx context.Context, m *hazelcastv1alpha1.Map, logger logr.Logger) error {
+	if !controllerutil.ContainsFinalizer(m, n.Finalizer) && m.GetDeletionTimestamp() == nil {
+		controllerutil.AddFinalizer(m, n.Finalizer)
+		err := r.Update(ctx, m)
+		if err != nil {
+			return err
+		}
+		logger.V(util.DebugLevel).Info("Finalizer added into custom resource successfully")
+	}
+	return nil
+}
+
+// executeFinalizer removes the map according to its deletion policy and then removes the finalizer.
+// With the Delete policy the map is destroyed and the finalizer is kept until the map config is removed from the persisted configuration.
+func (r *MapReconciler) executeFinalizer(ctx context.Context, m *hazelcastv1alpha1.Map, logger logr.Logger) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(m, n.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if m.Spec.DeletionPolicy == hazelcastv1alpha1.DeletionPolicyDelete {
+		done, err := r.deleteMap(ctx, m, logger)
+		if err != nil {
+			return updateMapStatus(ctx, r.Client, m, terminatingStatus(retryAfterForMap).withMessage(err.Error()))
+		}
+		if !done {
+			return updateMapStatus(ctx, r.Client, m, terminatingStatus(terminatingRetryAfter(m)).
+				withMessage("Waiting for the map config to be removed from the persisted configuration."))
+		}
+	}
+
+	controllerutil.RemoveFinalizer(m, n.Finalizer)
+	err := r.Update(ctx, m)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove finalizer from custom resource: %w", err)
+	}
+	metrics.DeleteMapConfigFailures(m, leftMembers(m.Status.MemberStatuses, nil, nil))
+	logger.V(util.DebugLevel).Info("Finalizer's pre-delete function executed successfully and the finalizer removed from custom resource", "Name:", n.Finalizer)
+	return ctrl.Result{}, nil
+}
+
+// deleteMap destroys the map in the cluster and returns true when the map config is no longer in the persisted configuration.
+// The map is destroyed once, then only the persisted configuration is polled.
+// The Terminating status of the Map triggers the Hazelcast reconciler, which leaves the map out of the persisted configuration.
+// If the Hazelcast resource is not Running, its reconciler may not reach the ConfigMap, so the map is released once destroyed.
+// The persisted configuration is rewritten without the deleted Map on the next successful reconcile of the Hazelcast resource.
+// The map config itself cannot be removed from the running members, so it stays in the cluster until the members are restarted.
+func (r *MapReconciler) deleteMap(ctx context.Context, m *hazelcastv1alpha1.Map, logger logr.Logger) (bool, error) {
+	h := &hazelcastv1alpha1.Hazelcast{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: m.Namespace, Name: m.Spec.HazelcastResourceName}, h)
+	if errors.IsNotFound(err) || (err == nil && h.GetDeletionTimestamp() != nil) {
+		// The cluster is gone together with the map
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := m.Annotations[n.MapDestroyedAnnotation]; !ok {
+		if isClusterDown(h) {
+			logger.Info("Cluster is down, the map is not destroyed. Its data is removed when the members restart without it.",
+				"name", m.MapName())
+		} else if err = destroyMap(ctx, m); err != nil {
+			return false, err
+		}
+		if m.Annotations == nil {
+			m.Annotations = map[string]string{}
+		}
+		m.Annotations[n.MapDestroyedAnnotation] = "true"
+		if err = r.Update(ctx, m); err != nil {
+			return false, err
+		}
+	}
+
+	if h.Status.Phase != hazelcastv1alpha1.Running {
+		logger.Info("Hazelcast resource is not running, the map config is removed from the persisted configuration on its next reconcile.",
+			"name", m.MapName(), "phase", h.Status.Phase)
+		return true, nil
+	}
+	return r.isMapConfigRemoved(ctx, m)
+}
+
+// terminatingRetryAfter returns the time to wait before polling the persisted configuration again.
+// It grows with the time passed since the Map was deleted, from a second up to maxRetryAfterForMapDeletion.
+func terminatingRetryAfter(m *hazelcastv1alpha1.Map) time.Duration {
+	if m.DeletionTimestamp == nil {
+		return time.Second
+	}
+	d := time.Since(m.DeletionTimestamp.Time)
+	if d < time.Second {
+		return time.Second
+	}
+	if d > maxRetryAfterForMapDeletion {
+		return maxRetryAfterForMapDeletion
+	}
+	return d
+}
+
+func destroyMap(ctx context.Context, m *hazelcastv1alpha1.Map) error {
+	cl, err := GetHazelcastClient(m)
+	if err != nil {
+		return err
+	}
+	mp, err := cl.GetMap(ctx, m.MapName())
+	if err != nil {
+		return err
+	}
+	if err = mp.Destroy(ctx); err != nil {
+		return fmt.Errorf("could not destroy the map %s: %w", m.MapName(), err)
+	}
+	return nil
+}
+
+// isClusterDown returns true if the operator is not connected to the cluster and none of its members is ready,
+// so that the map cannot be destroyed.
+func isClusterDown(h *hazelcastv1alpha1.Hazelcast) bool {
+	c := meta.FindStatusCondition(h.Status.Conditions, hazelcastv1alpha1.ClientConnected)
+	if c == nil || c.Status != metav1.ConditionFalse {
+		return false
+	}
+	for _, member := range h.Status.Members {
+		if member.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *MapReconciler) isMapConfigRemoved(ctx context.Context, m *hazelcastv1alpha1.Map) (bool, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: m.Spec.HazelcastResourceName, Namespace: m.Namespace}, cm)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	hzConfig := &config.HazelcastWrapper{}
+	err = yaml.Unmarshal([]byte(cm.Data["hazelcast.yaml"]), hzConfig)
+	if err != nil {
+		return false, fmt.Errorf("persisted ConfigMap is not formatted correctly")
+	}
+	_, ok := hzConfig.Hazelcast.Map[m.MapName()]
+	return !ok, nil
 }
 
 func ValidatePersistence(pe bool, h *hazelcastv1alpha1.Hazelcast) error {
@@ -165,6 +396,81 @@ func ValidatePersistence(pe bool, h *hazelcastv1alpha1.Hazelcast) error {
 	return nil
 }
 
+func ValidateMapSpec(ms *hazelcastv1alpha1.MapSpec, h *hazelcastv1alpha1.Hazelcast) error {
+	if *ms.BackupCount+ms.AsyncBackupCount > n.MaxMapBackupCount {
+		return fmt.Errorf("the sum of backupCount and asyncBackupCount cannot be greater than %d", n.MaxMapBackupCount)
+	}
+	// NATIVE requires native memory on the members, which cannot be enabled on the Hazelcast resource
+	if ms.GetInMemoryFormat() == hazelcastv1alpha1.InMemoryFormatNative {
+		return fmt.Errorf("inMemoryFormat NATIVE is not supported since native memory is not enabled on the members")
+	}
+	if ms.NearCache != nil && ms.NearCache.GetInMemoryFormat() == hazelcastv1alpha1.InMemoryFormatNative {
+		return fmt.Errorf("nearCache.inMemoryFormat NATIVE is not supported since native memory is not enabled on the members")
+	}
+	if ms.MerkleTree != nil && !util.IsEnterprise(h.Spec.Repository) {
+		return fmt.Errorf("merkleTree requires Hazelcast Enterprise")
+	}
+	if ms.WanReplicationRef != nil && !util.IsEnterprise(h.Spec.Repository) {
+		return fmt.Errorf("wanReplicationRef requires Hazelcast Enterprise")
+	}
+	return validateQueryCaches(ms.QueryCaches)
+}
+
+func validateQueryCaches(qcs []hazelcastv1alpha1.QueryCacheConfig) error {
+	names := map[string]bool{}
+	for _, qc := range qcs {
+		if names[qc.Name] {
+			return fmt.Errorf("queryCaches contains duplicate name %s", qc.Name)
+		}
+		names[qc.Name] = true
+		if (qc.Predicate.SQL == "") == (qc.Predicate.ClassName == "") {
+			return fmt.Errorf("exactly one of sql and className must be set in the predicate of query cache %s", qc.Name)
+		}
+		if qc.Predicate.SQL == "" {
+			continue
+		}
+		if err := validation.ValidateSQLPredicate(qc.Predicate.SQL); err != nil {
+			return fmt.Errorf("invalid predicate of query cache %s: %w", qc.Name, err)
+		}
+	}
+	return nil
+}
+
+// validateWanReplicationRef checks that the WAN replication referenced by the map is defined with a publisher
+// in the custom configuration of the Hazelcast resource.
+func (r *MapReconciler) validateWanReplicationRef(ctx context.Context, ms *hazelcastv1alpha1.MapSpec, h *hazelcastv1alpha1.Hazelcast) error {
+	if ms.WanReplicationRef == nil {
+		return nil
+	}
+	custom, err := customConfig(ctx, r.Client, h)
+	if err != nil {
+		return err
+	}
+	if !hasWanPublisher(custom, ms.WanReplicationRef.Name) {
+		return fmt.Errorf("WAN replication %s with a publisher is not defined in the custom config of the Hazelcast resource %s", ms.WanReplicationRef.Name, h.Name)
+	}
+	return nil
+}
+
+// hasWanPublisher returns true if the given Hazelcast configuration defines the WAN replication with a batch or custom publisher.
+func hasWanPublisher(cfg string, name string) bool {
+	c := struct {
+		Hazelcast struct {
+			WanReplication map[string]map[string]interface{} `yaml:"wan-replication"`
+		} `yaml:"hazelcast"`
+	}{}
+	if err := yaml.Unmarshal([]byte(cfg), &c); err != nil {
+		return false
+	}
+	wan, ok := c.Hazelcast.WanReplication[name]
+	if !ok {
+		return false
+	}
+	_, batch := wan["batch-publisher"]
+	_, custom := wan["custom-publisher"]
+	return batch || custom
+}
+
 func ValidateNotUpdatableFields(current *hazelcastv1alpha1.MapSpec, last *hazelcastv1alpha1.MapSpec) error {
 	if current.Name != last.Name {
 		return fmt.Errorf("name cannot be updated.")
@@ -172,8 +478,38 @@ func ValidateNotUpdatableFields(current *hazelcastv1alpha1.MapSpec, last *hazelc
 	if *current.BackupCount != *last.BackupCount {
 		return fmt.Errorf("backupCount cannot be updated.")
 	}
-	if !util.IndexConfigSliceEquals(current.Indexes, last.Indexes) {
-		return fmt.Errorf("indexes cannot be updated.")
+	if current.AsyncBackupCount != last.AsyncBackupCount {
+		return fmt.Errorf("asyncBackupCount cannot be updated.")
+	}
+	if current.GetInMemoryFormat() != last.GetInMemoryFormat() {
+		return fmt.Errorf("inMemoryFormat cannot be updated.")
+	}
+	if current.IsStatisticsEnabled() != last.IsStatisticsEnabled() {
+		return fmt.Errorf("statisticsEnabled cannot be updated.")
+	}
+	if !reflect.DeepEqual(current.NearCache, last.NearCache) {
+		return fmt.Errorf("nearCache cannot be updated.")
+	}
+	if !reflect.DeepEqual(current.MapStore, last.MapStore) {
+		return fmt.Errorf("mapStore cannot be updated.")
+	}
+	if !util.EntryListenerConfigSliceEquals(current.EntryListeners, last.EntryListeners) {
+		return fmt.Errorf("entryListeners cannot be updated.")
+	}
+	if !reflect.DeepEqual(current.EventJournal, last.EventJournal) {
+		return fmt.Errorf("eventJournal cannot be updated.")
+	}
+	if !reflect.DeepEqual(current.MerkleTree, last.MerkleTree) {
+		return fmt.Errorf("merkleTree cannot be updated.")
+	}
+	if !reflect.DeepEqual(current.WanReplicationRef, last.WanReplicationRef) {
+		return fmt.Errorf("wanReplicationRef cannot be updated.")
+	}
+	if !reflect.DeepEqual(current.QueryCaches, last.QueryCaches) {
+		return fmt.Errorf("queryCaches cannot be updated.")
+	}
+	if _, err := util.AddedIndexConfigs(current.Indexes, last.Indexes); err != nil {
+		return err
 	}
 	if current.PersistenceEnabled != last.PersistenceEnabled {
 		return fmt.Errorf("persistenceEnabled cannot be updated.")
@@ -205,13 +541,20 @@ func (r *MapReconciler) ReconcileMapConfig(ctx context.Context, m *hazelcastv1al
 			*m.Spec.TimeToLiveSeconds,
 			*m.Spec.MaxIdleSeconds,
 			hazelcastv1alpha1.EncodeEvictionPolicyType[m.Spec.Eviction.EvictionPolicy],
-			false,
+			m.Spec.ReadBackupData,
 			*m.Spec.Eviction.MaxSize,
 			hazelcastv1alpha1.EncodeMaxSizePolicy[m.Spec.Eviction.MaxSizePolicy],
 		)
 	} else {
 		mapInput := codecTypes.DefaultAddMapConfigInput()
-		fillAddMapConfigInput(mapInput, m)
+		props, err := mapStoreProperties(ctx, r.Client, m.Namespace, propertiesSecretName(m.Spec.MapStore))
+		if err != nil {
+			return nil, err
+		}
+		// The config must be the one registered when the map was created, the indexes added later are added separately
+		rm := m.DeepCopy()
+		rm.Spec.Indexes = registeredIndexes(m)
+		fillAddMapConfigInput(mapInput, rm, props)
 		req = codec.EncodeDynamicConfigAddMapConfigRequest(mapInput)
 	}
 
@@ -224,12 +567,14 @@ func (r *MapReconciler) ReconcileMapConfig(ctx context.Context, m *hazelcastv1al
 		}
 		_, err := ci.InvokeOnMember(ctx, req, member.UUID, nil)
 		if err != nil {
+			metrics.IncMapConfigFailures(m, member.UUID.String())
 			memberStatuses[member.UUID.String()] = hazelcastv1alpha1.MapFailed
 			failedMembers.WriteString(member.UUID.String() + ", ")
 			continue
 		}
 		memberStatuses[member.UUID.String()] = hazelcastv1alpha1.MapSuccess
 	}
+	metrics.DeleteMapConfigFailures(m, leftMembers(m.Status.MemberStatuses, memberStatuses, nil))
 	errString := failedMembers.String()
 	if errString != "" {
 		return memberStatuses, fmt.Errorf("error creating/updating the Map config %s for members %s", m.MapName(), errString[:len(errString)-2])
@@ -238,13 +583,163 @@ func (r *MapReconciler) ReconcileMapConfig(ctx context.Context, m *hazelcastv1al
 	return memberStatuses, nil
 }
 
-func fillAddMapConfigInput(mapInput *codecTypes.AddMapConfigInput, m *hazelcastv1alpha1.Map) {
+// activeMemberStatuses returns the member statuses of the Map without the members that left the cluster,
+// together with the members that joined the cluster since the map config was applied.
+// The members are the ones seen by the last status polling of the Hazelcast client.
+func activeMemberStatuses(m *hazelcastv1alpha1.Map) (map[string]hazelcastv1alpha1.MapConfigState, []string) {
+	hzcl, ok := GetClient(types.NamespacedName{Name: m.Spec.HazelcastResourceName, Namespace: m.Namespace})
+	if !ok {
+		return m.Status.MemberStatuses, nil
+	}
+	hzcl.Lock()
+	members := memberIDs(hzcl.Status.MemberMap)
+	hzcl.Unlock()
+	return filterMemberStatuses(m.Status.MemberStatuses, members)
+}
+
+// currentMapStats returns the statistics of the map collected by the last status polling of the Hazelcast client,
+// or nil if they are disabled or not reported by the members.
+func currentMapStats(m *hazelcastv1alpha1.Map) *hazelcastv1alpha1.MapStats {
+	if m.Spec.GetStatsRefreshIntervalSeconds() == 0 {
+		return nil
+	}
+	hzcl, ok := GetClient(types.NamespacedName{Name: m.Spec.HazelcastResourceName, Namespace: m.Namespace})
+	if !ok {
+		return nil
+	}
+	hzcl.Lock()
+	ls, ok := hzcl.Status.MapStats[m.MapName()]
+	hzcl.Unlock()
+	if !ok {
+		return nil
+	}
+	return mapStats(ls)
+}
+
+func mapStats(ls LocalMapStats) *hazelcastv1alpha1.MapStats {
+	stats := &hazelcastv1alpha1.MapStats{
+		OwnedEntryCount:      ls.OwnedEntryCount,
+		BackupEntryCount:     ls.BackupEntryCount,
+		OwnedEntryMemoryCost: ls.OwnedEntryMemoryCost,
+		Hits:                 ls.Hits,
+	}
+	if ls.LastUpdateTime > 0 {
+		t := metav1.NewTime(time.Unix(0, ls.LastUpdateTime*int64(time.Millisecond)))
+		stats.LastUpdateTime = &t
+	}
+	return stats
+}
+
+// refreshInterval returns when the applied Map is reconciled again, either for the next drift detection
+// or to refresh the statistics, 0 if both are disabled. Both are scheduled at their own interval.
+func refreshInterval(m *hazelcastv1alpha1.Map, now time.Time) time.Duration {
+	next := time.Duration(m.Spec.GetStatsRefreshIntervalSeconds()) * time.Second
+	if drift := time.Duration(m.Spec.DriftDetection.GetIntervalSeconds()) * time.Second; drift != 0 {
+		untilDrift := drift
+		if last := m.Status.LastDriftCheckTime; last != nil {
+			untilDrift = drift - now.Sub(last.Time)
+		}
+		if untilDrift < time.Second {
+			untilDrift = time.Second
+		}
+		if next == 0 || untilDrift < next {
+			next = untilDrift
+		}
+	}
+	return next
+}
+
+// isDriftCheckDue returns true if the drift detection is enabled and its interval elapsed since the last check.
+func isDriftCheckDue(m *hazelcastv1alpha1.Map, now time.Time) bool {
+	interval := time.Duration(m.Spec.DriftDetection.GetIntervalSeconds()) * time.Second
+	if interval == 0 {
+		return false
+	}
+	last := m.Status.LastDriftCheckTime
+	return last == nil || now.Sub(last.Time) >= interval
+}
+
+// membersLackingMap returns the given members whose map config differs from the spec, and marks the other ones as applied.
+// The members missing from the statuses, e.g. after an upgrade of the operator or a rolling restart,
+// usually know the map from the persisted config already, and adding it again with a different config would fail.
+// MCGetMapConfig returns the default map config to the members which lack the map, so they differ from the spec as well.
+func membersLackingMap(ctx context.Context, m *hazelcastv1alpha1.Map, members []string) ([]string, error) {
+	cl, err := GetHazelcastClient(m)
+	if err != nil {
+		return nil, err
+	}
+	ci := hazelcast.NewClientInternal(cl)
+	candidates := make(map[string]bool, len(members))
+	for _, member := range members {
+		candidates[member] = true
+	}
+
+	var lacking []string
+	for _, member := range ci.OrderedMembers() {
+		uuid := member.UUID.String()
+		if !candidates[uuid] {
+			continue
+		}
+		resp, err := ci.InvokeOnMember(ctx, codec.EncodeMCGetMapConfigRequest(m.MapName()), member.UUID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(mapConfigDiff(&m.Spec, codec.DecodeMCGetMapConfigResponse(resp))) != 0 {
+			lacking = append(lacking, uuid)
+			continue
+		}
+		if m.Status.MemberStatuses == nil {
+			m.Status.MemberStatuses = map[string]hazelcastv1alpha1.MapConfigState{}
+		}
+		m.Status.MemberStatuses[uuid] = hazelcastv1alpha1.MapSuccess
+	}
+	sort.Strings(lacking)
+	return lacking, nil
+}
+
+// leftMembers returns the members of the previous statuses that are neither in the current statuses nor joined.
+func leftMembers(previous, current map[string]hazelcastv1alpha1.MapConfigState, joined []string) []string {
+	var left []string
+	for member := range previous {
+		if _, ok := current[member]; ok || containsString(joined, member) {
+			continue
+		}
+		left = append(left, member)
+	}
+	sort.Strings(left)
+	return left
+}
+
+func filterMemberStatuses(statuses map[string]hazelcastv1alpha1.MapConfigState, members []string) (map[string]hazelcastv1alpha1.MapConfigState, []string) {
+	if len(members) == 0 {
+		// The members are not known until the client is connected
+		return statuses, nil
+	}
+	active := make(map[string]hazelcastv1alpha1.MapConfigState, len(members))
+	var joined []string
+	for _, member := range members {
+		status, ok := statuses[member]
+		if !ok || status != hazelcastv1alpha1.MapSuccess {
+			joined = append(joined, member)
+			continue
+		}
+		active[member] = status
+	}
+	sort.Strings(joined)
+	return active, joined
+}
+
+func fillAddMapConfigInput(mapInput *codecTypes.AddMapConfigInput, m *hazelcastv1alpha1.Map, mapStoreProps map[string]string) {
 	mapInput.Name = m.MapName()
 
 	ms := m.Spec
 	mapInput.BackupCount = *ms.BackupCount
+	mapInput.AsyncBackupCount = ms.AsyncBackupCount
 	mapInput.TimeToLiveSeconds = *ms.TimeToLiveSeconds
 	mapInput.MaxIdleSeconds = *ms.MaxIdleSeconds
+	mapInput.ReadBackupData = ms.ReadBackupData
+	mapInput.InMemoryFormat = string(ms.GetInMemoryFormat())
+	mapInput.StatisticsEnabled = ms.IsStatisticsEnabled()
 	if ms.Eviction != nil {
 		mapInput.EvictionConfig.EvictionPolicy = string(ms.Eviction.EvictionPolicy)
 		mapInput.EvictionConfig.Size = *ms.Eviction.MaxSize
@@ -252,7 +747,288 @@ func fillAddMapConfigInput(mapInput *codecTypes.AddMapConfigInput, m *hazelcastv
 	}
 	mapInput.IndexConfigs = copyIndexes(ms.Indexes)
 	mapInput.HotRestartConfig.Enabled = ms.PersistenceEnabled
+	mapInput.NearCacheConfig = nearCacheConfigHolder(ms.NearCache)
+	mapInput.MapStoreConfig = mapStoreConfigHolder(ms.MapStore, mapStoreProps)
+	mapInput.ListenerConfigs = entryListenerConfigHolders(ms.EntryListeners)
+	mapInput.QueryCacheConfigs = queryCacheConfigHolders(ms.QueryCaches)
+	if ms.MerkleTree != nil {
+		mapInput.MerkleTreeConfig = codecTypes.MerkleTreeConfig{
+			IsDefined:  true,
+			Enabled:    true,
+			Depth:      ms.MerkleTree.GetDepth(),
+			EnabledSet: true,
+		}
+	}
+	if ms.WanReplicationRef != nil {
+		mapInput.WanReplicationRef = codecTypes.WanReplicationRef{
+			Name:                 ms.WanReplicationRef.Name,
+			MergePolicyClassName: ms.WanReplicationRef.GetMergePolicyClassName(),
+			RepublishingEnabled:  ms.WanReplicationRef.IsRepublishingEnabled(),
+			Filters:              ms.WanReplicationRef.Filters,
+		}
+	}
+	if ms.EventJournal != nil {
+		mapInput.EventJournalConfig = codecTypes.EventJournalConfig{
+			IsDefined:         true,
+			Enabled:           ms.EventJournal.Enabled,
+			Capacity:          ms.EventJournal.GetCapacity(),
+			TimeToLiveSeconds: ms.EventJournal.TimeToLiveSeconds,
+		}
+	}
+}
+
+func entryListenerConfigHolders(elcs []hazelcastv1alpha1.EntryListenerConfig) []codecTypes.ListenerConfigHolder {
+	if len(elcs) == 0 {
+		return nil
+	}
+	holders := make([]codecTypes.ListenerConfigHolder, len(elcs))
+	for i, elc := range elcs {
+		holders[i] = codecTypes.ListenerConfigHolder{
+			ListenerType: int32(codecTypes.ListenerConfigTypeEntry),
+			ClassName:    elc.ClassName,
+			IncludeValue: elc.IsIncludeValue(),
+			Local:        elc.Local,
+		}
+	}
+	return holders
+}
+
+func mapStoreConfigHolder(msc *hazelcastv1alpha1.MapStoreConfig, props map[string]string) codecTypes.MapStoreConfigHolder {
+	if msc == nil {
+		return codecTypes.MapStoreConfigHolder{}
+	}
+	return codecTypes.MapStoreConfigHolder{
+		Enabled:           true,
+		ClassName:         msc.ClassName,
+		WriteDelaySeconds: msc.WriteDelaySeconds,
+		WriteBatchSize:    msc.GetWriteBatchSize(),
+		WriteCoalescing:   msc.IsWriteCoalescing(),
+		InitialLoadMode:   string(msc.GetInitialLoadMode()),
+		Properties:        props,
+	}
+}
+
+func queryCacheConfigHolders(qcs []hazelcastv1alpha1.QueryCacheConfig) []codecTypes.QueryCacheConfigHolder {
+	if len(qcs) == 0 {
+		return nil
+	}
+	holders := make([]codecTypes.QueryCacheConfigHolder, len(qcs))
+	for i, qc := range qcs {
+		holders[i] = queryCacheConfigHolder(qc)
+	}
+	return holders
+}
+
+// queryCacheConfigHolder returns the configuration of the query cache, using the defaults for the unset values.
+func queryCacheConfigHolder(qc hazelcastv1alpha1.QueryCacheConfig) codecTypes.QueryCacheConfigHolder {
+	holder := codecTypes.QueryCacheConfigHolder{
+		Name:           qc.Name,
+		BatchSize:      n.DefaultQueryCacheBatchSize,
+		BufferSize:     n.DefaultQueryCacheBufferSize,
+		IncludeValue:   true,
+		Populate:       qc.IsPopulate(),
+		Coalesce:       qc.Coalesce,
+		InMemoryFormat: n.DefaultMapInMemoryFormat,
+		PredicateConfigHolder: codecTypes.PredicateConfigHolder{
+			ClassName: qc.Predicate.ClassName,
+			Sql:       qc.Predicate.SQL,
+		},
+		EvictionConfigHolder: codecTypes.EvictionConfigHolder{
+			Size:           n.DefaultQueryCacheSize,
+			MaxSizePolicy:  n.DefaultQueryCacheMaxSizePolicy,
+			EvictionPolicy: n.DefaultQueryCacheEvictionPolicy,
+		},
+		IndexConfigs: copyIndexes(qc.Indexes),
+	}
+	if qc.BatchSize != 0 {
+		holder.BatchSize = qc.BatchSize
+	}
+	if qc.BufferSize != 0 {
+		holder.BufferSize = qc.BufferSize
+	}
+	return holder
+}
+
+func propertiesSecretName(msc *hazelcastv1alpha1.MapStoreConfig) string {
+	if msc == nil {
+		return ""
+	}
+	return msc.PropertiesSecretName
+}
+
+// mapStoreProperties returns the data of the given Secret as the MapStore properties, nil if no Secret is given.
+func mapStoreProperties(ctx context.Context, c client.Client, namespace, name string) (map[string]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	s := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, s)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the MapStore properties from Secret %s: %w", name, err)
+	}
+	props := make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		props[k] = string(v)
+	}
+	return props, nil
+}
+
+// addIndexes adds the new indexes to the existing map. Each index is created on all partitions, so on every member.
+// Adding an index that already exists with the same config has no effect, so it is safe to retry.
+func addIndexes(ctx context.Context, m *hazelcastv1alpha1.Map, cl *hazelcast.Client, indexes []hazelcastv1alpha1.IndexConfig) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+	mp, err := cl.GetMap(ctx, m.MapName())
+	if err != nil {
+		return err
+	}
+	for _, ic := range indexes {
+		if err = mp.AddIndex(ctx, clientIndexConfig(ic)); err != nil {
+			return fmt.Errorf("could not add index %s to the Map %s: %w", util.IndexKey(ic), m.MapName(), err)
+		}
+	}
+	return nil
+}
+
+func clientIndexConfig(ic hazelcastv1alpha1.IndexConfig) hztypes.IndexConfig {
+	c := hztypes.IndexConfig{
+		Name:       ic.Name,
+		Attributes: ic.Attributes,
+		Type:       hztypes.IndexType(hazelcastv1alpha1.EncodeIndexType[ic.Type]),
+	}
+	if ic.BitmapIndexOptions != nil {
+		c.BitmapIndexOptions = hztypes.BitmapIndexOptions{
+			UniqueKey:               ic.BitmapIndexOptions.UniqueKey,
+			UniqueKeyTransformation: hztypes.UniqueKeyTransformation(hazelcastv1alpha1.EncodeUniqueKeyTransition[ic.BitmapIndexOptions.UniqueKeyTransition]),
+		}
+	}
+	return c
+}
+
+// addedIndexKeys returns the keys of the indexes added to the existing map, including the ones recorded by the previous updates,
+// or nil if no index was added so that the status is kept.
+func addedIndexKeys(recorded []string, added []hazelcastv1alpha1.IndexConfig) []string {
+	if len(added) == 0 {
+		return nil
+	}
+	keys := append([]string{}, recorded...)
+	for _, ic := range added {
+		key := util.IndexKey(ic)
+		if !containsString(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// runtimeAddedIndexes returns the indexes of the spec that were added to the existing map.
+// They are not part of the map config registered on the members.
+func runtimeAddedIndexes(m *hazelcastv1alpha1.Map) []hazelcastv1alpha1.IndexConfig {
+	var indexes []hazelcastv1alpha1.IndexConfig
+	for _, ic := range m.Spec.Indexes {
+		if containsString(m.Status.AddedIndexes, util.IndexKey(ic)) {
+			indexes = append(indexes, ic)
+		}
+	}
+	return indexes
+}
+
+// registeredIndexes returns the indexes of the spec that are part of the map config registered on the members.
+func registeredIndexes(m *hazelcastv1alpha1.Map) []hazelcastv1alpha1.IndexConfig {
+	var indexes []hazelcastv1alpha1.IndexConfig
+	for _, ic := range m.Spec.Indexes {
+		if !containsString(m.Status.AddedIndexes, util.IndexKey(ic)) {
+			indexes = append(indexes, ic)
+		}
+	}
+	return indexes
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
 
+// checkMapInitialization creates the map on the members, which instantiates the entry listeners and the MapStore.
+// It returns an error if a listener class is not available to the members or the eager initial load of the MapStore fails.
+func checkMapInitialization(ctx context.Context, m *hazelcastv1alpha1.Map, cl *hazelcast.Client) error {
+	eager := m.Spec.MapStore != nil && m.Spec.MapStore.GetInitialLoadMode() == hazelcastv1alpha1.InitialLoadModeEager
+	if !eager && len(m.Spec.EntryListeners) == 0 {
+		return nil
+	}
+	mp, err := cl.GetMap(ctx, m.MapName())
+	if err != nil {
+		return fmt.Errorf("map %s could not be initialized on the members: %w", m.MapName(), err)
+	}
+	if !eager {
+		return nil
+	}
+	if _, err = mp.Size(ctx); err != nil {
+		return fmt.Errorf("MapStore initial load failed: %w", err)
+	}
+	return nil
+}
+
+func nearCacheConfigHolder(nc *hazelcastv1alpha1.NearCacheConfig) codecTypes.NearCacheConfigHolder {
+	if nc == nil {
+		return codecTypes.NearCacheConfigHolder{}
+	}
+	policy, size := nearCacheEviction(nc)
+	return codecTypes.NearCacheConfigHolder{
+		Name:               n.DefaultNearCacheName,
+		InMemoryFormat:     string(nc.GetInMemoryFormat()),
+		SerializeKeys:      false,
+		InvalidateOnChange: nc.IsInvalidateOnChange(),
+		TimeToLiveSeconds:  nc.TimeToLiveSeconds,
+		MaxIdleSeconds:     nc.MaxIdleSeconds,
+		EvictionConfigHolder: codecTypes.EvictionConfigHolder{
+			Size:           size,
+			MaxSizePolicy:  n.DefaultNearCacheMaxSizePolicy,
+			EvictionPolicy: policy,
+		},
+		CacheLocalEntries: nc.CacheLocalEntries,
+		LocalUpdatePolicy: n.DefaultNearCacheLocalUpdatePolicy,
+		PreloaderConfig:   nearCachePreloader(nc),
+	}
+}
+
+// nearCacheEviction returns the eviction policy and the max size of the Near Cache, using the defaults for the unset values.
+func nearCacheEviction(nc *hazelcastv1alpha1.NearCacheConfig) (string, int32) {
+	policy, size := n.DefaultNearCacheEvictionPolicy, n.DefaultNearCacheSize
+	if nc.Eviction != nil {
+		if nc.Eviction.EvictionPolicy != "" {
+			policy = string(nc.Eviction.EvictionPolicy)
+		}
+		if nc.Eviction.Size != 0 {
+			size = nc.Eviction.Size
+		}
+	}
+	return policy, size
+}
+
+// nearCachePreloader returns the preloader configuration of the Near Cache, using the defaults for the unset values.
+func nearCachePreloader(nc *hazelcastv1alpha1.NearCacheConfig) codecTypes.NearCachePreloaderConfig {
+	if nc.Preloader == nil || !nc.Preloader.Enabled {
+		return codecTypes.NearCachePreloaderConfig{}
+	}
+	p := codecTypes.NearCachePreloaderConfig{
+		Enabled:                  true,
+		Directory:                nc.Preloader.Directory,
+		StoreInitialDelaySeconds: n.DefaultNearCachePreloaderDelaySeconds,
+		StoreIntervalSeconds:     n.DefaultNearCachePreloaderIntervalSeconds,
+	}
+	if nc.Preloader.StoreInitialDelaySeconds != 0 {
+		p.StoreInitialDelaySeconds = nc.Preloader.StoreInitialDelaySeconds
+	}
+	if nc.Preloader.StoreIntervalSeconds != 0 {
+		p.StoreIntervalSeconds = nc.Preloader.StoreIntervalSeconds
+	}
+	return p
 }
 
 func copyIndexes(idx []hazelcastv1alpha1.IndexConfig) []codecTypes.IndexConfig {
@@ -308,7 +1084,7 @@ func (r *MapReconciler) validateMapConfigPersistence(ctx context.Context, m *haz
 	}
 
 	if mcfg, ok := hzConfig.Hazelcast.Map[m.MapName()]; !ok {
-		currentMcfg := createMapConfig(&m.Spec)
+		currentMcfg := createMapConfig(m)
 		if !reflect.DeepEqual(mcfg, currentMcfg) { // TODO replace DeepEqual with custom implementation
 			return false, nil
 		}
@@ -320,5 +1096,53 @@ func (r *MapReconciler) validateMapConfigPersistence(ctx context.Context, m *haz
 func (r *MapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&hazelcastv1alpha1.Map{}).
+		Watches(&source.Kind{Type: &hazelcastv1alpha1.Hazelcast{}}, handler.EnqueueRequestsFromMapFunc(r.hazelcastUpdates),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+				UpdateFunc:  membersChanged,
+			})).
 		Complete(r)
 }
+
+// hazelcastUpdates returns the Maps of the given Hazelcast resource.
+func (r *MapReconciler) hazelcastUpdates(h client.Object) []reconcile.Request {
+	mapList := &hazelcastv1alpha1.MapList{}
+	err := r.Client.List(context.Background(), mapList,
+		client.InNamespace(h.GetNamespace()),
+		client.MatchingFields{"hazelcastResourceName": h.GetName()})
+	if err != nil {
+		return []reconcile.Request{}
+	}
+	reqs := make([]reconcile.Request, 0, len(mapList.Items))
+	for _, m := range mapList.Items {
+		reqs = append(reqs, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: m.Name, Namespace: m.Namespace},
+		})
+	}
+	return reqs
+}
+
+// membersChanged returns true if a member left or joined the Hazelcast cluster, e.g. when it was restarted.
+func membersChanged(e event.UpdateEvent) bool {
+	oldH, ok := e.ObjectOld.(*hazelcastv1alpha1.Hazelcast)
+	if !ok {
+		return false
+	}
+	newH, ok := e.ObjectNew.(*hazelcastv1alpha1.Hazelcast)
+	if !ok {
+		return false
+	}
+	return !reflect.DeepEqual(readyMemberUids(oldH), readyMemberUids(newH))
+}
+
+func readyMemberUids(h *hazelcastv1alpha1.Hazelcast) map[string]bool {
+	uids := map[string]bool{}
+	for _, m := range h.Status.Members {
+		if m.Ready && m.Uid != "" {
+			uids[m.Uid] = true
+		}
+	}
+	return uids
+}
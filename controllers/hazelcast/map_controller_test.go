@@ -0,0 +1,660 @@
+package hazelcast
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/config"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
+	codecTypes "github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
+)
+
+func Test_ValidateMapSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		spec       hazelcastv1alpha1.MapSpec
+		wantErr    bool
+	}{
+		{
+			name:       "Default values",
+			repository: n.HazelcastRepo,
+			spec:       hazelcastv1alpha1.MapSpec{BackupCount: &[]int32{1}[0]},
+		},
+		{
+			name:       "Total backups within the limit",
+			repository: n.HazelcastRepo,
+			spec:       hazelcastv1alpha1.MapSpec{BackupCount: &[]int32{3}[0], AsyncBackupCount: 3},
+		},
+		{
+			name:       "Total backups above the limit",
+			repository: n.HazelcastRepo,
+			spec:       hazelcastv1alpha1.MapSpec{BackupCount: &[]int32{4}[0], AsyncBackupCount: 3},
+			wantErr:    true,
+		},
+		{
+			name:       "Native memory format with open source image",
+			repository: n.HazelcastRepo,
+			spec:       hazelcastv1alpha1.MapSpec{BackupCount: &[]int32{1}[0], InMemoryFormat: hazelcastv1alpha1.InMemoryFormatNative},
+			wantErr:    true,
+		},
+		{
+			name:       "Native memory format with enterprise image",
+			repository: n.HazelcastEERepo,
+			spec:       hazelcastv1alpha1.MapSpec{BackupCount: &[]int32{1}[0], InMemoryFormat: hazelcastv1alpha1.InMemoryFormatNative},
+			wantErr:    true,
+		},
+		{
+			name:       "Native memory Near Cache format with enterprise image",
+			repository: n.HazelcastEERepo,
+			spec: hazelcastv1alpha1.MapSpec{
+				BackupCount: &[]int32{1}[0],
+				NearCache:   &hazelcastv1alpha1.NearCacheConfig{InMemoryFormat: hazelcastv1alpha1.InMemoryFormatNative},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "Merkle tree with open source image",
+			repository: n.HazelcastRepo,
+			spec:       hazelcastv1alpha1.MapSpec{BackupCount: &[]int32{1}[0], MerkleTree: &hazelcastv1alpha1.MerkleTreeConfig{}},
+			wantErr:    true,
+		},
+		{
+			name:       "WAN replication with open source image",
+			repository: n.HazelcastRepo,
+			spec:       hazelcastv1alpha1.MapSpec{BackupCount: &[]int32{1}[0], WanReplicationRef: &hazelcastv1alpha1.WanReplicationRef{Name: "wan"}},
+			wantErr:    true,
+		},
+		{
+			name:       "Merkle tree and WAN replication with enterprise image",
+			repository: n.HazelcastEERepo,
+			spec: hazelcastv1alpha1.MapSpec{
+				BackupCount:       &[]int32{1}[0],
+				MerkleTree:        &hazelcastv1alpha1.MerkleTreeConfig{},
+				WanReplicationRef: &hazelcastv1alpha1.WanReplicationRef{Name: "wan"},
+			},
+		},
+		{
+			name:       "Query caches with valid predicates",
+			repository: n.HazelcastRepo,
+			spec: hazelcastv1alpha1.MapSpec{
+				BackupCount: &[]int32{1}[0],
+				QueryCaches: []hazelcastv1alpha1.QueryCacheConfig{
+					{Name: "adults", Predicate: hazelcastv1alpha1.QueryCachePredicate{SQL: "age >= 18"}},
+					{Name: "custom", Predicate: hazelcastv1alpha1.QueryCachePredicate{ClassName: "com.example.CustomPredicate"}},
+				},
+			},
+		},
+		{
+			name:       "Query cache with invalid SQL predicate",
+			repository: n.HazelcastRepo,
+			spec: hazelcastv1alpha1.MapSpec{
+				BackupCount: &[]int32{1}[0],
+				QueryCaches: []hazelcastv1alpha1.QueryCacheConfig{{Name: "adults", Predicate: hazelcastv1alpha1.QueryCachePredicate{SQL: "age >="}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "Query cache with both SQL and class predicates",
+			repository: n.HazelcastRepo,
+			spec: hazelcastv1alpha1.MapSpec{
+				BackupCount: &[]int32{1}[0],
+				QueryCaches: []hazelcastv1alpha1.QueryCacheConfig{{Name: "adults", Predicate: hazelcastv1alpha1.QueryCachePredicate{SQL: "age >= 18", ClassName: "com.example.CustomPredicate"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "Query caches with duplicate names",
+			repository: n.HazelcastRepo,
+			spec: hazelcastv1alpha1.MapSpec{
+				BackupCount: &[]int32{1}[0],
+				QueryCaches: []hazelcastv1alpha1.QueryCacheConfig{
+					{Name: "adults", Predicate: hazelcastv1alpha1.QueryCachePredicate{SQL: "age >= 18"}},
+					{Name: "adults", Predicate: hazelcastv1alpha1.QueryCachePredicate{SQL: "age >= 21"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hazelcastv1alpha1.Hazelcast{
+				Spec: hazelcastv1alpha1.HazelcastSpec{Repository: tt.repository},
+			}
+			if err := ValidateMapSpec(&tt.spec, h); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMapSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_nearCacheConfigHolderDefaults(t *testing.T) {
+	nc := &hazelcastv1alpha1.NearCacheConfig{
+		Eviction:  &hazelcastv1alpha1.NearCacheEviction{EvictionPolicy: hazelcastv1alpha1.EvictionPolicyLFU},
+		Preloader: &hazelcastv1alpha1.NearCachePreloader{Enabled: true, StoreIntervalSeconds: 60},
+	}
+	holder := nearCacheConfigHolder(nc)
+
+	if holder.InMemoryFormat != n.DefaultMapInMemoryFormat || !holder.InvalidateOnChange {
+		t.Errorf("Unexpected Near Cache defaults: %+v", holder)
+	}
+	if holder.EvictionConfigHolder.EvictionPolicy != "LFU" || holder.EvictionConfigHolder.Size != n.DefaultNearCacheSize {
+		t.Errorf("Unexpected Near Cache eviction: %+v", holder.EvictionConfigHolder)
+	}
+	if holder.PreloaderConfig.StoreInitialDelaySeconds != n.DefaultNearCachePreloaderDelaySeconds || holder.PreloaderConfig.StoreIntervalSeconds != 60 {
+		t.Errorf("Unexpected Near Cache preloader: %+v", holder.PreloaderConfig)
+	}
+	if nearCacheConfigHolder(nil).Name != "" {
+		t.Errorf("Expected an empty Near Cache config when it is not set")
+	}
+}
+
+func Test_mapStoreConfigHolder(t *testing.T) {
+	msc := &hazelcastv1alpha1.MapStoreConfig{ClassName: "com.example.PersonStore", WriteDelaySeconds: 5}
+	props := map[string]string{"url": "jdbc:postgresql://db/people"}
+	holder := mapStoreConfigHolder(msc, props)
+
+	if !holder.Enabled || holder.ClassName != msc.ClassName || holder.WriteDelaySeconds != 5 {
+		t.Errorf("Unexpected MapStore config: %+v", holder)
+	}
+	if holder.WriteBatchSize != 1 || !holder.WriteCoalescing || holder.InitialLoadMode != "LAZY" {
+		t.Errorf("Unexpected MapStore defaults: %+v", holder)
+	}
+	if holder.Properties["url"] != props["url"] {
+		t.Errorf("Unexpected MapStore properties: %v", holder.Properties)
+	}
+	if mapStoreConfigHolder(nil, nil).Enabled {
+		t.Errorf("Expected an empty MapStore config when it is not set")
+	}
+}
+
+func Test_mapStoreProperties(t *testing.T) {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "map-store-props", Namespace: "default"},
+		Data:       map[string][]byte{"user": []byte("admin"), "password": []byte("secret")},
+	}
+	c := fakeClient(s)
+
+	props, err := mapStoreProperties(context.Background(), c, "default", s.Name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(props) != 2 || props["user"] != "admin" || props["password"] != "secret" {
+		t.Errorf("Unexpected MapStore properties: %v", props)
+	}
+
+	props, err = mapStoreProperties(context.Background(), c, "default", "")
+	if err != nil || props != nil {
+		t.Errorf("Expected no properties without a Secret, got %v, %v", props, err)
+	}
+
+	if _, err = mapStoreProperties(context.Background(), c, "default", "missing"); err == nil {
+		t.Errorf("Expected an error for a missing Secret")
+	}
+}
+
+func Test_entryListenerConfigHolders(t *testing.T) {
+	elcs := []hazelcastv1alpha1.EntryListenerConfig{
+		{ClassName: "com.example.AuditListener"},
+		{ClassName: "com.example.LocalListener", IncludeValue: &[]bool{false}[0], Local: true},
+	}
+	holders := entryListenerConfigHolders(elcs)
+
+	if len(holders) != 2 {
+		t.Fatalf("Expected 2 listener configs, got %d", len(holders))
+	}
+	for _, h := range holders {
+		if h.ListenerType != int32(codecTypes.ListenerConfigTypeEntry) {
+			t.Errorf("Unexpected listener type: %+v", h)
+		}
+	}
+	if holders[0].ClassName != elcs[0].ClassName || !holders[0].IncludeValue || holders[0].Local {
+		t.Errorf("Unexpected listener config: %+v", holders[0])
+	}
+	if holders[1].ClassName != elcs[1].ClassName || holders[1].IncludeValue || !holders[1].Local {
+		t.Errorf("Unexpected listener config: %+v", holders[1])
+	}
+	if entryListenerConfigHolders(nil) != nil {
+		t.Errorf("Expected no listener configs when they are not set")
+	}
+}
+
+func Test_createMapConfigEventJournal(t *testing.T) {
+	m := &hazelcastv1alpha1.Map{
+		ObjectMeta: metav1.ObjectMeta{Name: "journal", Namespace: "default"},
+		Spec: hazelcastv1alpha1.MapSpec{
+			BackupCount:       &[]int32{1}[0],
+			TimeToLiveSeconds: &[]int32{0}[0],
+			MaxIdleSeconds:    &[]int32{0}[0],
+			Eviction:          &hazelcastv1alpha1.EvictionConfig{MaxSize: &[]int32{0}[0]},
+			EventJournal:      &hazelcastv1alpha1.EventJournalConfig{Enabled: true, TimeToLiveSeconds: 60},
+		},
+	}
+	mcfg := createMapConfig(m)
+
+	want := config.EventJournal{Enabled: true, Capacity: 10000, TimeToLiveSeconds: 60}
+	if mcfg.EventJournal == nil || *mcfg.EventJournal != want {
+		t.Errorf("EventJournal = %+v, want %+v", mcfg.EventJournal, want)
+	}
+
+	mapInput := codecTypes.DefaultAddMapConfigInput()
+	fillAddMapConfigInput(mapInput, m, nil)
+	if ej := mapInput.EventJournalConfig; !ej.IsDefined || !ej.Enabled || ej.Capacity != 10000 || ej.TimeToLiveSeconds != 60 {
+		t.Errorf("Unexpected Event Journal in the dynamic config: %+v", ej)
+	}
+}
+
+func Test_hasWanPublisher(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  string
+		want bool
+	}{
+		{
+			name: "Batch publisher",
+			cfg:  "hazelcast:\n  wan-replication:\n    london:\n      batch-publisher:\n        london-dc:\n          target-endpoints: 10.0.0.1\n",
+			want: true,
+		},
+		{
+			name: "Custom publisher",
+			cfg:  "hazelcast:\n  wan-replication:\n    london:\n      custom-publisher:\n        kafka:\n          class-name: com.example.KafkaPublisher\n",
+			want: true,
+		},
+		{
+			name: "Other WAN replication",
+			cfg:  "hazelcast:\n  wan-replication:\n    tokyo:\n      batch-publisher:\n        tokyo-dc: {}\n",
+		},
+		{
+			name: "WAN replication without publisher",
+			cfg:  "hazelcast:\n  wan-replication:\n    london: {}\n",
+		},
+		{
+			name: "No custom config",
+			cfg:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasWanPublisher(tt.cfg, "london"); got != tt.want {
+				t.Errorf("hasWanPublisher() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_queryCacheConfigHolderDefaults(t *testing.T) {
+	qc := hazelcastv1alpha1.QueryCacheConfig{
+		Name:       "adults",
+		Predicate:  hazelcastv1alpha1.QueryCachePredicate{SQL: "age >= 18"},
+		BufferSize: 64,
+	}
+	holder := queryCacheConfigHolder(qc)
+
+	if holder.Name != "adults" || holder.PredicateConfigHolder.Sql != "age >= 18" || holder.PredicateConfigHolder.ClassName != "" {
+		t.Errorf("Unexpected query cache config: %+v", holder)
+	}
+	if holder.BatchSize != n.DefaultQueryCacheBatchSize || holder.BufferSize != 64 || !holder.Populate || holder.Coalesce {
+		t.Errorf("Unexpected query cache defaults: %+v", holder)
+	}
+	if holder.EvictionConfigHolder.Size != n.DefaultQueryCacheSize || holder.EvictionConfigHolder.EvictionPolicy != n.DefaultQueryCacheEvictionPolicy {
+		t.Errorf("Unexpected query cache eviction: %+v", holder.EvictionConfigHolder)
+	}
+}
+
+func Test_filterMemberStatuses(t *testing.T) {
+	statuses := map[string]hazelcastv1alpha1.MapConfigState{
+		"member-1": hazelcastv1alpha1.MapSuccess,
+		"member-2": hazelcastv1alpha1.MapSuccess,
+		"member-3": hazelcastv1alpha1.MapFailed,
+	}
+
+	active, joined := filterMemberStatuses(statuses, []string{"member-4", "member-3", "member-1"})
+	if !reflect.DeepEqual(active, map[string]hazelcastv1alpha1.MapConfigState{"member-1": hazelcastv1alpha1.MapSuccess}) {
+		t.Errorf("Unexpected active member statuses: %v", active)
+	}
+	if !reflect.DeepEqual(joined, []string{"member-3", "member-4"}) {
+		t.Errorf("Unexpected joined members: %v", joined)
+	}
+
+	// After a full restart none of the members applied the map config
+	_, joined = filterMemberStatuses(statuses, []string{"member-5", "member-6"})
+	if !reflect.DeepEqual(joined, []string{"member-5", "member-6"}) {
+		t.Errorf("Unexpected joined members after restart: %v", joined)
+	}
+
+	active, joined = filterMemberStatuses(statuses, nil)
+	if !reflect.DeepEqual(active, statuses) || joined != nil {
+		t.Errorf("Expected the member statuses to be kept when the members are unknown, got %v and %v", active, joined)
+	}
+}
+
+func Test_addedIndexKeys(t *testing.T) {
+	idx := func(name string) hazelcastv1alpha1.IndexConfig {
+		return hazelcastv1alpha1.IndexConfig{Name: name, Type: hazelcastv1alpha1.IndexTypeSorted, Attributes: []string{name}}
+	}
+
+	if keys := addedIndexKeys([]string{"index-1"}, nil); keys != nil {
+		t.Errorf("Expected the added indexes to be kept when no index is added, got %v", keys)
+	}
+	keys := addedIndexKeys([]string{"index-1"}, []hazelcastv1alpha1.IndexConfig{idx("index-1"), idx("index-2")})
+	if !reflect.DeepEqual(keys, []string{"index-1", "index-2"}) {
+		t.Errorf("Unexpected added indexes: %v", keys)
+	}
+
+	m := &hazelcastv1alpha1.Map{
+		Spec:   hazelcastv1alpha1.MapSpec{Indexes: []hazelcastv1alpha1.IndexConfig{idx("index-0"), idx("index-1")}},
+		Status: hazelcastv1alpha1.MapStatus{AddedIndexes: []string{"index-1"}},
+	}
+	if r := registeredIndexes(m); !reflect.DeepEqual(r, []hazelcastv1alpha1.IndexConfig{idx("index-0")}) {
+		t.Errorf("Unexpected registered indexes: %v", r)
+	}
+	if a := runtimeAddedIndexes(m); !reflect.DeepEqual(a, []hazelcastv1alpha1.IndexConfig{idx("index-1")}) {
+		t.Errorf("Unexpected runtime added indexes: %v", a)
+	}
+}
+
+func Test_leftMembers(t *testing.T) {
+	previous := map[string]hazelcastv1alpha1.MapConfigState{
+		"member-1": hazelcastv1alpha1.MapSuccess,
+		"member-2": hazelcastv1alpha1.MapFailed,
+		"member-3": hazelcastv1alpha1.MapSuccess,
+	}
+	current := map[string]hazelcastv1alpha1.MapConfigState{"member-1": hazelcastv1alpha1.MapSuccess}
+
+	if left := leftMembers(previous, current, []string{"member-2"}); !reflect.DeepEqual(left, []string{"member-3"}) {
+		t.Errorf("Unexpected left members: %v", left)
+	}
+	if left := leftMembers(previous, nil, nil); !reflect.DeepEqual(left, []string{"member-1", "member-2", "member-3"}) {
+		t.Errorf("Expected all the members when the Map is deleted, got %v", left)
+	}
+}
+
+func Test_membersChanged(t *testing.T) {
+	hz := func(members ...hazelcastv1alpha1.HazelcastMemberStatus) *hazelcastv1alpha1.Hazelcast {
+		return &hazelcastv1alpha1.Hazelcast{Status: hazelcastv1alpha1.HazelcastStatus{Members: members}}
+	}
+	m1 := hazelcastv1alpha1.HazelcastMemberStatus{Uid: "member-1", Ip: "10.0.0.1", Ready: true}
+	m2 := hazelcastv1alpha1.HazelcastMemberStatus{Uid: "member-2", Ip: "10.0.0.2", Ready: true}
+	restarted := hazelcastv1alpha1.HazelcastMemberStatus{Uid: "member-3", Ip: "10.0.0.2", Ready: true}
+	updated := m2
+	updated.OwnedPartitions = 135
+
+	tests := []struct {
+		name string
+		old  *hazelcastv1alpha1.Hazelcast
+		new  *hazelcastv1alpha1.Hazelcast
+		want bool
+	}{
+		{name: "No change", old: hz(m1, m2), new: hz(m2, m1), want: false},
+		{name: "Member data updated", old: hz(m1, m2), new: hz(m1, updated), want: false},
+		{name: "Member restarted", old: hz(m1, m2), new: hz(m1, restarted), want: true},
+		{name: "Member joined", old: hz(m1), new: hz(m1, m2), want: true},
+		{name: "Member left", old: hz(m1, m2), new: hz(m1), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := membersChanged(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new}); got != tt.want {
+				t.Errorf("membersChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_refreshInterval(t *testing.T) {
+	seconds := func(s int32) *int32 { return &s }
+	now := time.Now()
+	tests := []struct {
+		name      string
+		drift     *int32
+		stats     *int32
+		lastCheck time.Duration
+		want      time.Duration
+	}{
+		{name: "Defaults", want: 30 * time.Second},
+		{name: "Drift detection more often", drift: seconds(10), want: 10 * time.Second},
+		{name: "Drift detection due before the statistics", drift: seconds(60), lastCheck: 50 * time.Second, want: 10 * time.Second},
+		{name: "Drift detection overdue", drift: seconds(60), lastCheck: 90 * time.Second, want: time.Second},
+		{name: "Statistics disabled", stats: seconds(0), want: 60 * time.Second},
+		{name: "Drift detection disabled", drift: seconds(0), stats: seconds(45), want: 45 * time.Second},
+		{name: "Both disabled", drift: seconds(0), stats: seconds(0), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &hazelcastv1alpha1.Map{
+				Spec: hazelcastv1alpha1.MapSpec{
+					DriftDetection:              &hazelcastv1alpha1.DriftDetectionConfig{IntervalSeconds: tt.drift},
+					StatsRefreshIntervalSeconds: tt.stats,
+				},
+			}
+			if tt.lastCheck != 0 {
+				m.Status.LastDriftCheckTime = &metav1.Time{Time: now.Add(-tt.lastCheck)}
+			}
+			if got := refreshInterval(m, now); got != tt.want {
+				t.Errorf("refreshInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_driftDetectionDisabledUnlessConfigured(t *testing.T) {
+	m := &hazelcastv1alpha1.Map{Spec: hazelcastv1alpha1.MapSpec{StatsRefreshIntervalSeconds: &[]int32{0}[0]}}
+	if isDriftCheckDue(m, time.Now()) {
+		t.Errorf("Expected no drift check without the drift detection configuration")
+	}
+	if got := refreshInterval(m, time.Now()); got != 0 {
+		t.Errorf("refreshInterval() = %v, want 0", got)
+	}
+}
+
+func Test_isDriftCheckDue(t *testing.T) {
+	now := time.Now()
+	m := &hazelcastv1alpha1.Map{
+		Spec: hazelcastv1alpha1.MapSpec{
+			DriftDetection: &hazelcastv1alpha1.DriftDetectionConfig{IntervalSeconds: &[]int32{60}[0]},
+		},
+	}
+	if !isDriftCheckDue(m, now) {
+		t.Errorf("Expected the drift check to be due when it was never done")
+	}
+	// A refresh of the statistics does not check the drift
+	m.Status.LastDriftCheckTime = &metav1.Time{Time: now.Add(-30 * time.Second)}
+	if isDriftCheckDue(m, now) {
+		t.Errorf("Expected the drift check not to be due before its interval")
+	}
+	m.Status.LastDriftCheckTime = &metav1.Time{Time: now.Add(-60 * time.Second)}
+	if !isDriftCheckDue(m, now) {
+		t.Errorf("Expected the drift check to be due after its interval")
+	}
+}
+
+func Test_mapStats(t *testing.T) {
+	stats := mapStats(LocalMapStats{OwnedEntryCount: 5, OwnedEntryMemoryCost: 512, LastUpdateTime: 1650000000123})
+	if stats.OwnedEntryCount != 5 || stats.OwnedEntryMemoryCost != 512 {
+		t.Errorf("Unexpected map stats: %+v", stats)
+	}
+	if stats.LastUpdateTime == nil || !stats.LastUpdateTime.Time.Equal(time.Unix(1650000000, 123*int64(time.Millisecond))) {
+		t.Errorf("Unexpected last update time: %v", stats.LastUpdateTime)
+	}
+
+	if stats = mapStats(LocalMapStats{}); stats.LastUpdateTime != nil {
+		t.Errorf("Expected no last update time for a map never updated, got %v", stats.LastUpdateTime)
+	}
+}
+
+func Test_mapFinalizerWithoutHazelcast(t *testing.T) {
+	now := metav1.Now()
+	m := &hazelcastv1alpha1.Map{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "map",
+			Namespace:         "default",
+			Finalizers:        []string{n.Finalizer},
+			DeletionTimestamp: &now,
+		},
+		Spec: hazelcastv1alpha1.MapSpec{
+			HazelcastResourceName: "hazelcast",
+			DeletionPolicy:        hazelcastv1alpha1.DeletionPolicyDelete,
+		},
+	}
+	c := fakeClient(m)
+	r := &MapReconciler{Client: c, Log: ctrl.Log}
+
+	if _, err := r.executeFinalizer(context.Background(), m, ctrl.Log); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := &hazelcastv1alpha1.Map{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got.Finalizers) != 0 {
+		t.Errorf("Expected the finalizer to be removed when the Hazelcast resource does not exist, got %v", got.Finalizers)
+	}
+}
+
+func Test_mapFinalizerDestroysMapOnce(t *testing.T) {
+	now := metav1.Now()
+	terminatingMap := func(annotations map[string]string) *hazelcastv1alpha1.Map {
+		return &hazelcastv1alpha1.Map{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "map",
+				Namespace:         "default",
+				Annotations:       annotations,
+				Finalizers:        []string{n.Finalizer},
+				DeletionTimestamp: &now,
+			},
+			Spec: hazelcastv1alpha1.MapSpec{
+				HazelcastResourceName: "hazelcast-terminating-map",
+				DeletionPolicy:        hazelcastv1alpha1.DeletionPolicyDelete,
+			},
+		}
+	}
+	hazelcast := func(connected metav1.ConditionStatus, members ...hazelcastv1alpha1.HazelcastMemberStatus) *hazelcastv1alpha1.Hazelcast {
+		return &hazelcastv1alpha1.Hazelcast{
+			ObjectMeta: metav1.ObjectMeta{Name: "hazelcast-terminating-map", Namespace: "default"},
+			Status: hazelcastv1alpha1.HazelcastStatus{
+				Conditions: []metav1.Condition{{Type: hazelcastv1alpha1.ClientConnected, Status: connected}},
+				Members:    members,
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		m           *hazelcastv1alpha1.Map
+		h           *hazelcastv1alpha1.Hazelcast
+		wantRemoved bool
+	}{
+		{
+			name:        "Cluster is down",
+			m:           terminatingMap(nil),
+			h:           hazelcast(metav1.ConditionFalse),
+			wantRemoved: true,
+		},
+		{
+			name:        "Map already destroyed",
+			m:           terminatingMap(map[string]string{n.MapDestroyedAnnotation: "true"}),
+			h:           hazelcast(metav1.ConditionTrue),
+			wantRemoved: true,
+		},
+		{
+			name: "Cluster is unreachable but members are ready",
+			m:    terminatingMap(nil),
+			h:    hazelcast(metav1.ConditionFalse, hazelcastv1alpha1.HazelcastMemberStatus{Ready: true}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fakeClient(tt.m, tt.h)
+			r := &MapReconciler{Client: c, Log: ctrl.Log}
+
+			_, _ = r.executeFinalizer(context.Background(), tt.m, ctrl.Log)
+			got := &hazelcastv1alpha1.Map{}
+			if err := c.Get(context.Background(), types.NamespacedName{Name: tt.m.Name, Namespace: tt.m.Namespace}, got); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if removed := len(got.Finalizers) == 0; removed != tt.wantRemoved {
+				t.Errorf("Finalizer removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if _, ok := got.Annotations[n.MapDestroyedAnnotation]; ok != tt.wantRemoved {
+				t.Errorf("Map destroyed annotation set = %v, want %v", ok, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func Test_mapFinalizerReleasedWhenHazelcastIsNotRunning(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name        string
+		phase       hazelcastv1alpha1.Phase
+		wantRemoved bool
+	}{
+		{name: "Hazelcast is running", phase: hazelcastv1alpha1.Running},
+		{name: "Hazelcast is failed", phase: hazelcastv1alpha1.Failed, wantRemoved: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &hazelcastv1alpha1.Map{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "map",
+					Namespace:         "default",
+					Annotations:       map[string]string{n.MapDestroyedAnnotation: "true"},
+					Finalizers:        []string{n.Finalizer},
+					DeletionTimestamp: &now,
+				},
+				Spec: hazelcastv1alpha1.MapSpec{
+					HazelcastResourceName: "hazelcast-failed-map",
+					DeletionPolicy:        hazelcastv1alpha1.DeletionPolicyDelete,
+				},
+			}
+			h := &hazelcastv1alpha1.Hazelcast{
+				ObjectMeta: metav1.ObjectMeta{Name: "hazelcast-failed-map", Namespace: "default"},
+				Status:     hazelcastv1alpha1.HazelcastStatus{Phase: tt.phase},
+			}
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "hazelcast-failed-map", Namespace: "default"},
+				Data:       map[string]string{"hazelcast.yaml": "hazelcast:\n  map:\n    map: {}\n"},
+			}
+			c := fakeClient(m, h, cm)
+			r := &MapReconciler{Client: c, Log: ctrl.Log}
+
+			res, err := r.executeFinalizer(context.Background(), m, ctrl.Log)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got := &hazelcastv1alpha1.Map{}
+			if err = c.Get(context.Background(), types.NamespacedName{Name: m.Name, Namespace: m.Namespace}, got); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if removed := len(got.Finalizers) == 0; removed != tt.wantRemoved {
+				t.Errorf("Finalizer removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if !tt.wantRemoved && res.RequeueAfter < time.Second {
+				t.Errorf("Expected the persisted configuration to be polled again, got %v", res.RequeueAfter)
+			}
+		})
+	}
+}
+
+func Test_terminatingRetryAfter(t *testing.T) {
+	deletedAgo := func(d time.Duration) *hazelcastv1alpha1.Map {
+		ts := metav1.NewTime(time.Now().Add(-d))
+		return &hazelcastv1alpha1.Map{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &ts}}
+	}
+	if d := terminatingRetryAfter(deletedAgo(0)); d != time.Second {
+		t.Errorf("Expected a second right after the deletion, got %v", d)
+	}
+	if d := terminatingRetryAfter(deletedAgo(10 * time.Second)); d < 10*time.Second || d > 11*time.Second {
+		t.Errorf("Expected the retry to grow with the time since the deletion, got %v", d)
+	}
+	if d := terminatingRetryAfter(deletedAgo(time.Hour)); d != maxRetryAfterForMapDeletion {
+		t.Errorf("Expected the retry to be capped at %v, got %v", maxRetryAfterForMapDeletion, d)
+	}
+}
@@ -0,0 +1,203 @@
+package hazelcast
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/hazelcast/hazelcast-go-client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	"github.com/hazelcast/hazelcast-platform-operator/internal/protocol/codec"
+	codecTypes "github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
+)
+
+// detectDrift compares the map config of each member with the spec and reports the differences in the status.
+// The spec is re-applied to the drifted members if it is enabled in the drift detection configuration.
+// It is called on each refresh of an applied Map, which also updates the map statistics in the status,
+// but the comparison is done only at the drift detection interval.
+func (r *MapReconciler) detectDrift(ctx context.Context, m *hazelcastv1alpha1.Map, logger logr.Logger) (ctrl.Result, error) {
+	now := time.Now()
+	if m.Spec.DriftDetection.GetIntervalSeconds() == 0 {
+		m.Status.LastDriftCheckTime = nil
+		return updateMapStatus(ctx, r.Client, m, successStatus().
+			withMemberStatuses(m.Status.MemberStatuses).
+			withRetryAfter(refreshInterval(m, now)))
+	}
+	if !isDriftCheckDue(m, now) {
+		// Only the statistics are refreshed, the result of the last check is kept
+		o := successStatus()
+		if m.Status.State == hazelcastv1alpha1.MapDrifted {
+			o = driftedStatus(0).
+				withMessage(m.Status.Message).
+				withDriftedFields(m.Status.DriftedFields)
+		}
+		return updateMapStatus(ctx, r.Client, m, o.
+			withMemberStatuses(m.Status.MemberStatuses).
+			withRetryAfter(refreshInterval(m, now)))
+	}
+
+	cl, err := GetHazelcastClient(m)
+	if err != nil {
+		return updateMapStatus(ctx, r.Client, m, successStatus().
+			withMessage(fmt.Sprintf("Could not check the map config of the members: %s", err.Error())).
+			withMemberStatuses(m.Status.MemberStatuses).
+			withRetryAfter(retryAfterForMap))
+	}
+
+	drifted, err := mapConfigDrifts(ctx, m, cl)
+	if err == nil && m.Spec.DriftDetection.IsReapply() {
+		// Fields which cannot be updated at runtime stay drifted, they alone do not trigger a re-apply
+		if updatable := updatableDrifts(drifted); len(updatable) != 0 {
+			logger.Info("Re-applying the map config to the drifted members", "members", driftedMembers(updatable))
+			err = reapplyMapConfig(ctx, m, cl, updatable)
+			if err == nil {
+				drifted, err = mapConfigDrifts(ctx, m, cl)
+			}
+		}
+	}
+	if err != nil {
+		return updateMapStatus(ctx, r.Client, m, successStatus().
+			withMessage(fmt.Sprintf("Could not check the map config of the members: %s", err.Error())).
+			withMemberStatuses(m.Status.MemberStatuses).
+			withRetryAfter(retryAfterForMap))
+	}
+	m.Status.LastDriftCheckTime = &metav1.Time{Time: now}
+	interval := refreshInterval(m, now)
+	if len(drifted) == 0 {
+		return updateMapStatus(ctx, r.Client, m, successStatus().
+			withMemberStatuses(m.Status.MemberStatuses).
+			withRetryAfter(interval))
+	}
+
+	members := driftedMembers(drifted)
+	logger.Info("Map config of the members differs from the spec", "members", members)
+	return updateMapStatus(ctx, r.Client, m, driftedStatus(interval).
+		withMessage(fmt.Sprintf("Map config differs from the spec on members %s", strings.Join(members, ", "))).
+		withMemberStatuses(m.Status.MemberStatuses).
+		withDriftedFields(drifted))
+}
+
+func driftedMembers(drifted map[string][]string) []string {
+	members := make([]string, 0, len(drifted))
+	for member := range drifted {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// updatableFields are the fields of the map config which can be updated at runtime with MCUpdateMapConfig.
+var updatableFields = map[string]bool{
+	"timeToLiveSeconds":       true,
+	"maxIdleSeconds":          true,
+	"readBackupData":          true,
+	"eviction.maxSize":        true,
+	"eviction.maxSizePolicy":  true,
+	"eviction.evictionPolicy": true,
+}
+
+// updatableDrifts returns the drifted fields of the members which have drifted in at least one updatable field.
+func updatableDrifts(drifted map[string][]string) map[string][]string {
+	updatable := map[string][]string{}
+	for member, fields := range drifted {
+		for _, f := range fields {
+			if updatableFields[strings.SplitN(f, ":", 2)[0]] {
+				updatable[member] = fields
+				break
+			}
+		}
+	}
+	return updatable
+}
+
+// mapConfigDrifts returns the fields of the map config that differ from the spec, per member UUID.
+func mapConfigDrifts(ctx context.Context, m *hazelcastv1alpha1.Map, cl *hazelcast.Client) (map[string][]string, error) {
+	ci := hazelcast.NewClientInternal(cl)
+	drifted := map[string][]string{}
+	for _, member := range ci.OrderedMembers() {
+		req := codec.EncodeMCGetMapConfigRequest(m.MapName())
+		resp, err := ci.InvokeOnMember(ctx, req, member.UUID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if fields := mapConfigDiff(&m.Spec, codec.DecodeMCGetMapConfigResponse(resp)); len(fields) != 0 {
+			drifted[member.UUID.String()] = fields
+		}
+	}
+	return drifted, nil
+}
+
+// mapConfigDiff returns the fields of the live map config that differ from the spec in the form "field: spec value != live value".
+func mapConfigDiff(ms *hazelcastv1alpha1.MapSpec, mc codecTypes.MapConfig) []string {
+	var diff []string
+	add := func(field string, want, got interface{}) {
+		if want != got {
+			diff = append(diff, fmt.Sprintf("%s: %v != %v", field, want, got))
+		}
+	}
+	if ms.BackupCount != nil {
+		add("backupCount", *ms.BackupCount, mc.BackupCount)
+	}
+	add("asyncBackupCount", ms.AsyncBackupCount, mc.AsyncBackupCount)
+	if ms.TimeToLiveSeconds != nil {
+		add("timeToLiveSeconds", *ms.TimeToLiveSeconds, mc.TimeToLiveSeconds)
+	}
+	if ms.MaxIdleSeconds != nil {
+		add("maxIdleSeconds", *ms.MaxIdleSeconds, mc.MaxIdleSeconds)
+	}
+	add("readBackupData", ms.ReadBackupData, mc.ReadBackupData)
+	add("inMemoryFormat", string(ms.GetInMemoryFormat()), decodeEnum(hazelcastv1alpha1.EncodeInMemoryFormat, mc.InMemoryFormat))
+	if ms.Eviction != nil {
+		if ms.Eviction.MaxSize != nil {
+			add("eviction.maxSize", *ms.Eviction.MaxSize, mc.MaxSize)
+		}
+		if ms.Eviction.MaxSizePolicy != "" {
+			add("eviction.maxSizePolicy", string(ms.Eviction.MaxSizePolicy), decodeEnum(hazelcastv1alpha1.EncodeMaxSizePolicy, mc.MaxSizePolicy))
+		}
+		if ms.Eviction.EvictionPolicy != "" {
+			add("eviction.evictionPolicy", string(ms.Eviction.EvictionPolicy), decodeEnum(hazelcastv1alpha1.EncodeEvictionPolicyType, mc.EvictionPolicy))
+		}
+	}
+	return diff
+}
+
+// decodeEnum returns the name of the value in the given Encode* map of the API, or the value itself if it is unknown.
+func decodeEnum(encode interface{}, v int32) string {
+	iter := reflect.ValueOf(encode).MapRange()
+	for iter.Next() {
+		if iter.Value().Int() == int64(v) {
+			return iter.Key().String()
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// reapplyMapConfig sends the updatable fields of the spec to the drifted members.
+func reapplyMapConfig(ctx context.Context, m *hazelcastv1alpha1.Map, cl *hazelcast.Client, drifted map[string][]string) error {
+	ci := hazelcast.NewClientInternal(cl)
+	req := codec.EncodeMCUpdateMapConfigRequest(
+		m.MapName(),
+		*m.Spec.TimeToLiveSeconds,
+		*m.Spec.MaxIdleSeconds,
+		hazelcastv1alpha1.EncodeEvictionPolicyType[m.Spec.Eviction.EvictionPolicy],
+		m.Spec.ReadBackupData,
+		*m.Spec.Eviction.MaxSize,
+		hazelcastv1alpha1.EncodeMaxSizePolicy[m.Spec.Eviction.MaxSizePolicy],
+	)
+	for _, member := range ci.OrderedMembers() {
+		if _, ok := drifted[member.UUID.String()]; !ok {
+			continue
+		}
+		if _, err := ci.InvokeOnMember(ctx, req, member.UUID, nil); err != nil {
+			return fmt.Errorf("could not re-apply the map config to member %s: %w", member.UUID.String(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package hazelcast
+
+import (
+	"reflect"
+	"testing"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	codecTypes "github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
+)
+
+func Test_mapConfigDiff(t *testing.T) {
+	ms := &hazelcastv1alpha1.MapSpec{
+		BackupCount:       &[]int32{1}[0],
+		TimeToLiveSeconds: &[]int32{0}[0],
+		MaxIdleSeconds:    &[]int32{0}[0],
+		Eviction: &hazelcastv1alpha1.EvictionConfig{
+			EvictionPolicy: hazelcastv1alpha1.EvictionPolicyNone,
+			MaxSize:        &[]int32{0}[0],
+			MaxSizePolicy:  hazelcastv1alpha1.MaxSizePolicyPerNode,
+		},
+	}
+	live := codecTypes.MapConfig{
+		BackupCount:    1,
+		MaxSizePolicy:  hazelcastv1alpha1.EncodeMaxSizePolicy[hazelcastv1alpha1.MaxSizePolicyPerNode],
+		EvictionPolicy: hazelcastv1alpha1.EncodeEvictionPolicyType[hazelcastv1alpha1.EvictionPolicyNone],
+	}
+
+	if diff := mapConfigDiff(ms, live); len(diff) != 0 {
+		t.Errorf("Expected no drift, got %v", diff)
+	}
+
+	live.TimeToLiveSeconds = 300
+	live.EvictionPolicy = hazelcastv1alpha1.EncodeEvictionPolicyType[hazelcastv1alpha1.EvictionPolicyLRU]
+	want := []string{"timeToLiveSeconds: 0 != 300", "eviction.evictionPolicy: NONE != LRU"}
+	if diff := mapConfigDiff(ms, live); !reflect.DeepEqual(diff, want) {
+		t.Errorf("mapConfigDiff() = %v, want %v", diff, want)
+	}
+}
+
+func Test_updatableDrifts(t *testing.T) {
+	drifted := map[string][]string{
+		"member-1": {"backupCount: 1 != 2"},
+		"member-2": {"backupCount: 1 != 2", "timeToLiveSeconds: 0 != 300"},
+	}
+	want := map[string][]string{"member-2": drifted["member-2"]}
+	if got := updatableDrifts(drifted); !reflect.DeepEqual(got, want) {
+		t.Errorf("updatableDrifts() = %v, want %v", got, want)
+	}
+}
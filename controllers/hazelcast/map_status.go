@@ -17,6 +17,8 @@ type mapOptionsBuilder struct {
 	message        string
 	retryAfter     time.Duration
 	memberStatuses map[string]hazelcastv1alpha1.MapConfigState
+	addedIndexes   []string
+	driftedFields  map[string][]string
 }
 
 func failedStatus(err error) mapOptionsBuilder {
@@ -39,6 +41,20 @@ func pendingStatus(retryAfter time.Duration) mapOptionsBuilder {
 	}
 }
 
+func terminatingStatus(retryAfter time.Duration) mapOptionsBuilder {
+	return mapOptionsBuilder{
+		status:     hazelcastv1alpha1.MapTerminating,
+		retryAfter: retryAfter,
+	}
+}
+
+func driftedStatus(retryAfter time.Duration) mapOptionsBuilder {
+	return mapOptionsBuilder{
+		status:     hazelcastv1alpha1.MapDrifted,
+		retryAfter: retryAfter,
+	}
+}
+
 func persistingStatus(retryAfter time.Duration) mapOptionsBuilder {
 	return mapOptionsBuilder{
 		status:     hazelcastv1alpha1.MapPersisting,
@@ -61,10 +77,31 @@ func (o mapOptionsBuilder) withMemberStatuses(m map[string]hazelcastv1alpha1.Map
 	return o
 }
 
+func (o mapOptionsBuilder) withAddedIndexes(indexes []string) mapOptionsBuilder {
+	o.addedIndexes = indexes
+	return o
+}
+
+func (o mapOptionsBuilder) withDriftedFields(f map[string][]string) mapOptionsBuilder {
+	o.driftedFields = f
+	return o
+}
+
+// withRetryAfter sets when the next reconcile is done, e.g. the next drift detection for the Success state.
+func (o mapOptionsBuilder) withRetryAfter(retryAfter time.Duration) mapOptionsBuilder {
+	o.retryAfter = retryAfter
+	return o
+}
+
 func updateMapStatus(ctx context.Context, c client.Client, m *hazelcastv1alpha1.Map, options mapOptionsBuilder) (ctrl.Result, error) {
 	m.Status.State = options.status
 	m.Status.Message = options.message
 	m.Status.MemberStatuses = options.memberStatuses
+	m.Status.DriftedFields = options.driftedFields
+	m.Status.Stats = currentMapStats(m)
+	if options.addedIndexes != nil {
+		m.Status.AddedIndexes = options.addedIndexes
+	}
 	if err := c.Status().Update(ctx, m); err != nil {
 		// Conflicts are expected and will be handled on the next reconcile loop, no need to error out here
 		if errors.IsConflict(err) {
@@ -75,8 +112,9 @@ func updateMapStatus(ctx context.Context, c client.Client, m *hazelcastv1alpha1.
 	if options.status == hazelcastv1alpha1.MapFailed {
 		return ctrl.Result{}, options.err
 	}
-	if options.status == hazelcastv1alpha1.MapPending || options.status == hazelcastv1alpha1.MapPersisting {
+	if options.status == hazelcastv1alpha1.MapPending || options.status == hazelcastv1alpha1.MapPersisting ||
+		options.status == hazelcastv1alpha1.MapTerminating {
 		return ctrl.Result{Requeue: true, RequeueAfter: options.retryAfter}, nil
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: options.retryAfter}, nil
 }
@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidateSQLPredicate checks that the given predicate is a valid Hazelcast SQL predicate, such as
+// "active AND (age > 30 OR name LIKE 'J%')". The attributes are not checked since they are known only to the members.
+func ValidateSQLPredicate(sql string) error {
+	tokens, err := tokenizePredicate(sql)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("predicate is empty")
+	}
+	p := &predicateParser{tokens: tokens}
+	if err = p.parseOr(); err != nil {
+		return err
+	}
+	if !p.done() {
+		return fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return nil
+}
+
+type predicateTokenKind int
+
+const (
+	tokenIdentifier predicateTokenKind = iota
+	tokenKeyword
+	tokenLiteral
+	tokenOperator
+	tokenPunctuation
+)
+
+var predicateKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "LIKE": true, "ILIKE": true, "REGEX": true, "IN": true, "BETWEEN": true,
+}
+
+var predicateLiterals = map[string]bool{
+	"TRUE": true, "FALSE": true, "NULL": true,
+}
+
+var predicateOperators = []string{"==", "!=", "<>", "<=", ">=", "=", "<", ">"}
+
+type predicateToken struct {
+	kind predicateTokenKind
+	text string
+	pos  int
+}
+
+func tokenizePredicate(sql string) ([]predicateToken, error) {
+	var tokens []predicateToken
+	r := []rune(sql)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, predicateToken{tokenPunctuation, string(c), i})
+			i++
+		case c == '\'':
+			start := i
+			i++
+			for {
+				if i >= len(r) {
+					return nil, fmt.Errorf("unterminated string starting at position %d", start)
+				}
+				if r[i] == '\'' {
+					// Quotes are escaped by doubling them
+					if i+1 < len(r) && r[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, predicateToken{tokenLiteral, string(r[start:i]), start})
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(r) && unicode.IsDigit(r[i+1])):
+			start := i
+			i++
+			for i < len(r) && (unicode.IsDigit(r[i]) || r[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, predicateToken{tokenLiteral, string(r[start:i]), start})
+		case isIdentifierStart(c):
+			start := i
+			for i < len(r) && isIdentifierPart(r[i]) {
+				i++
+			}
+			word := string(r[start:i])
+			kind := tokenIdentifier
+			if predicateKeywords[strings.ToUpper(word)] {
+				kind = tokenKeyword
+				word = strings.ToUpper(word)
+			} else if predicateLiterals[strings.ToUpper(word)] {
+				kind = tokenLiteral
+			}
+			tokens = append(tokens, predicateToken{kind, word, start})
+		default:
+			op := ""
+			for _, o := range predicateOperators {
+				if strings.HasPrefix(string(r[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+			tokens = append(tokens, predicateToken{tokenOperator, op, i})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentifierStart(c rune) bool {
+	return unicode.IsLetter(c) || c == '_' || c == '$'
+}
+
+func isIdentifierPart(c rune) bool {
+	return isIdentifierStart(c) || unicode.IsDigit(c) || c == '.' || c == '[' || c == ']' || c == '#'
+}
+
+// predicateParser is a recursive descent parser of the SQL predicate grammar:
+//
+//	or         = and { OR and }
+//	and        = not { AND not }
+//	not        = NOT not | "(" or ")" | condition
+//	condition  = operand [ operator operand | [NOT] (LIKE | ILIKE | REGEX) string
+//	             | [NOT] BETWEEN operand AND operand | [NOT] IN "(" operand { "," operand } ")" ]
+type predicateParser struct {
+	tokens []predicateToken
+	pos    int
+}
+
+func (p *predicateParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *predicateParser) peek() predicateToken {
+	return p.tokens[p.pos]
+}
+
+func (p *predicateParser) accept(kind predicateTokenKind, text string) bool {
+	if !p.done() && p.peek().kind == kind && p.peek().text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *predicateParser) expect(kind predicateTokenKind, text string) error {
+	if p.accept(kind, text) {
+		return nil
+	}
+	if p.done() {
+		return fmt.Errorf("expected %q at the end of the predicate", text)
+	}
+	return fmt.Errorf("expected %q at position %d, found %q", text, p.peek().pos, p.peek().text)
+}
+
+func (p *predicateParser) parseOr() error {
+	if err := p.parseAnd(); err != nil {
+		return err
+	}
+	for p.accept(tokenKeyword, "OR") {
+		if err := p.parseAnd(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *predicateParser) parseAnd() error {
+	if err := p.parseNot(); err != nil {
+		return err
+	}
+	for p.accept(tokenKeyword, "AND") {
+		if err := p.parseNot(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *predicateParser) parseNot() error {
+	if p.accept(tokenKeyword, "NOT") {
+		return p.parseNot()
+	}
+	if p.accept(tokenPunctuation, "(") {
+		if err := p.parseOr(); err != nil {
+			return err
+		}
+		return p.expect(tokenPunctuation, ")")
+	}
+	return p.parseCondition()
+}
+
+func (p *predicateParser) parseCondition() error {
+	if err := p.parseOperand(); err != nil {
+		return err
+	}
+	if p.done() {
+		return nil
+	}
+	if p.peek().kind == tokenOperator {
+		p.pos++
+		return p.parseOperand()
+	}
+	negated := p.accept(tokenKeyword, "NOT")
+	switch {
+	case p.accept(tokenKeyword, "LIKE"), p.accept(tokenKeyword, "ILIKE"), p.accept(tokenKeyword, "REGEX"):
+		if p.done() || p.peek().kind != tokenLiteral || !strings.HasPrefix(p.peek().text, "'") {
+			return fmt.Errorf("expected a string pattern after %s", p.tokens[p.pos-1].text)
+		}
+		p.pos++
+	case p.accept(tokenKeyword, "BETWEEN"):
+		if err := p.parseOperand(); err != nil {
+			return err
+		}
+		if err := p.expect(tokenKeyword, "AND"); err != nil {
+			return err
+		}
+		return p.parseOperand()
+	case p.accept(tokenKeyword, "IN"):
+		if err := p.expect(tokenPunctuation, "("); err != nil {
+			return err
+		}
+		for {
+			if err := p.parseOperand(); err != nil {
+				return err
+			}
+			if !p.accept(tokenPunctuation, ",") {
+				break
+			}
+		}
+		return p.expect(tokenPunctuation, ")")
+	case negated:
+		return fmt.Errorf("expected LIKE, ILIKE, REGEX, BETWEEN or IN after NOT at position %d", p.tokens[p.pos-1].pos)
+	}
+	return nil
+}
+
+func (p *predicateParser) parseOperand() error {
+	if p.done() {
+		return fmt.Errorf("unexpected end of the predicate")
+	}
+	t := p.peek()
+	if t.kind != tokenIdentifier && t.kind != tokenLiteral {
+		return fmt.Errorf("expected an attribute or a value at position %d, found %q", t.pos, t.text)
+	}
+	p.pos++
+	return nil
+}
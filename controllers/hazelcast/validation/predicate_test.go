@@ -0,0 +1,38 @@
+package validation
+
+import "testing"
+
+func Test_ValidateSQLPredicate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantErr bool
+	}{
+		{name: "Boolean attribute", sql: "active"},
+		{name: "Comparison", sql: "age >= 30"},
+		{name: "Logical operators", sql: "active AND (age > 30 OR name = 'John')"},
+		{name: "Negation", sql: "NOT active and salary <> 100.5"},
+		{name: "Like", sql: "name LIKE 'J%' OR name NOT ILIKE 'a''b%'"},
+		{name: "Regex", sql: "email REGEX '.*@example\\.com'"},
+		{name: "Between", sql: "age BETWEEN 18 AND -1"},
+		{name: "In", sql: "id IN (1, 2, 3) AND __key.region NOT IN ('EU')"},
+		{name: "Nested attributes", sql: "address.city = 'London' AND values[any] > 5"},
+		{name: "Empty", sql: "  ", wantErr: true},
+		{name: "Missing operand", sql: "age >", wantErr: true},
+		{name: "Unbalanced parentheses", sql: "(age > 30", wantErr: true},
+		{name: "Unterminated string", sql: "name = 'John", wantErr: true},
+		{name: "Dangling operator", sql: "active AND", wantErr: true},
+		{name: "Like without pattern", sql: "name LIKE 5", wantErr: true},
+		{name: "Between without and", sql: "age BETWEEN 1 5", wantErr: true},
+		{name: "Not without condition operator", sql: "active NOT", wantErr: true},
+		{name: "Unknown character", sql: "age ~ 3", wantErr: true},
+		{name: "Missing logical operator", sql: "age > 3 name = 'x'", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSQLPredicate(tt.sql); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSQLPredicate(%q) error = %v, wantErr %v", tt.sql, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,7 +2,13 @@ package validation
 
 import (
 	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+	n "github.com/hazelcast/hazelcast-platform-operator/internal/naming"
 	"github.com/hazelcast/hazelcast-platform-operator/internal/util"
 )
 
@@ -15,6 +21,30 @@ func ValidateSpec(h *hazelcastv1alpha1.Hazelcast) error {
 		return err
 	}
 
+	if err := validateTLS(h); err != nil {
+		return err
+	}
+
+	if err := validateSecurity(h); err != nil {
+		return err
+	}
+
+	if err := validateJVM(h); err != nil {
+		return err
+	}
+
+	if err := validateHighAvailabilityMode(h); err != nil {
+		return err
+	}
+
+	if err := validateUpgrade(h); err != nil {
+		return err
+	}
+
+	if err := validateMetrics(h); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -38,6 +68,136 @@ func validateLicense(h *hazelcastv1alpha1.Hazelcast) error {
 	return nil
 }
 
+func validateSecurity(h *hazelcastv1alpha1.Hazelcast) error {
+	if !h.Spec.Security.IsEnabled() {
+		return nil
+	}
+	if !util.IsEnterprise(h.Spec.Repository) {
+		return errors.New("security is only available for Hazelcast Enterprise")
+	}
+	names := map[string]struct{}{}
+	for _, c := range h.Spec.Security.Clients {
+		if c.Name == n.OperatorIdentityName {
+			return fmt.Errorf("client identity name %q is reserved for the operator", c.Name)
+		}
+		if _, ok := names[c.Name]; ok {
+			return fmt.Errorf("client identity name %q is duplicated", c.Name)
+		}
+		names[c.Name] = struct{}{}
+	}
+	return nil
+}
+
+func validateTLS(h *hazelcastv1alpha1.Hazelcast) error {
+	if h.Spec.TLS.IsEnabled() && !util.IsEnterprise(h.Spec.Repository) {
+		return errors.New("TLS is only available for Hazelcast Enterprise")
+	}
+	return nil
+}
+
+// minHeap is the lowest heap size that can be passed to the JVM in mebibytes
+var minHeap = resource.MustParse("1Mi")
+
+func validateJVM(h *hazelcastv1alpha1.Hazelcast) error {
+	m := &hazelcastv1alpha1.JVMMemoryConfiguration{}
+	if h.Spec.JVM != nil && h.Spec.JVM.Memory != nil {
+		m = h.Spec.JVM.Memory
+	}
+	maxHeap := util.MaxHeap(h)
+	if maxHeap != nil && maxHeap.Cmp(minHeap) < 0 {
+		if m.MaxHeap != nil {
+			return errors.New("jvm.memory.maxHeap must be at least 1Mi")
+		}
+		return errors.New("resources.limits.memory is too low for a heap of at least 1Mi")
+	}
+	if m.InitialHeap != nil && m.InitialHeap.Cmp(minHeap) < 0 {
+		return errors.New("jvm.memory.initialHeap must be at least 1Mi")
+	}
+	if m.InitialHeap != nil && maxHeap != nil && m.InitialHeap.Cmp(*maxHeap) > 0 {
+		if m.MaxHeap != nil {
+			return errors.New("jvm.memory.initialHeap must not be greater than jvm.memory.maxHeap")
+		}
+		return fmt.Errorf("jvm.memory.initialHeap must not be greater than the maximum heap %s derived from resources.limits.memory", maxHeap.String())
+	}
+	if m.MaxHeap != nil && h.Spec.Resources != nil {
+		if limit, ok := h.Spec.Resources.Limits[corev1.ResourceMemory]; ok && m.MaxHeap.Cmp(limit) >= 0 {
+			return errors.New("jvm.memory.maxHeap must be less than resources.limits.memory")
+		}
+	}
+	return nil
+}
+
+func validateHighAvailabilityMode(h *hazelcastv1alpha1.Hazelcast) error {
+	var keys []string
+	switch h.Spec.HighAvailabilityMode {
+	case hazelcastv1alpha1.HighAvailabilityModeNode:
+		keys = []string{corev1.LabelHostname}
+	case hazelcastv1alpha1.HighAvailabilityModeZone:
+		keys = []string{corev1.LabelHostname, corev1.LabelTopologyZone}
+	default:
+		return nil
+	}
+
+	if h.Spec.ClusterSize != nil && *h.Spec.ClusterSize == 1 {
+		return fmt.Errorf("highAvailabilityMode %s requires at least 2 members", h.Spec.HighAvailabilityMode)
+	}
+	if h.Spec.Scheduling == nil {
+		return nil
+	}
+	for _, key := range keys {
+		if _, ok := h.Spec.Scheduling.NodeSelector[key]; ok {
+			return fmt.Errorf("highAvailabilityMode %s cannot be used with nodeSelector on %s, all members would be in the same topology domain", h.Spec.HighAvailabilityMode, key)
+		}
+	}
+	return nil
+}
+
+// validateUpgrade checks that the members running the last successfully applied version can be replaced one by one
+// with the members running the requested version.
+func validateUpgrade(h *hazelcastv1alpha1.Hazelcast) error {
+	lastSpec, ok := h.LastSuccessfulSpec()
+	if !ok || lastSpec.Version == h.Spec.Version {
+		return nil
+	}
+	current, ok := util.ParseVersion(lastSpec.Version)
+	if !ok {
+		return nil
+	}
+	target, ok := util.ParseVersion(h.Spec.Version)
+	if !ok {
+		return nil
+	}
+
+	if target.Compare(current) < 0 {
+		return fmt.Errorf("downgrading Hazelcast from %s to %s is not supported", lastSpec.Version, h.Spec.Version)
+	}
+	if target.Major != current.Major {
+		return fmt.Errorf("upgrading Hazelcast from %s to %s is not supported, major versions cannot be upgraded in a running cluster", lastSpec.Version, h.Spec.Version)
+	}
+	if target.Minor > current.Minor+1 {
+		return fmt.Errorf("upgrading Hazelcast from %s to %s is not supported, minor versions must be upgraded one at a time", lastSpec.Version, h.Spec.Version)
+	}
+	if target.Minor != current.Minor && !util.IsEnterprise(h.Spec.Repository) {
+		return errors.New("upgrading the minor version of a running cluster is only available for Hazelcast Enterprise")
+	}
+	return nil
+}
+
+func validateMetrics(h *hazelcastv1alpha1.Hazelcast) error {
+	if !h.Spec.Metrics.IsEnabled() {
+		return nil
+	}
+
+	port := h.Spec.Metrics.Port
+	if port == n.DefaultHzPort {
+		return fmt.Errorf("metrics.port must not be %d, it is used by Hazelcast", n.DefaultHzPort)
+	}
+	if h.Spec.Persistence.IsExternal() && port == n.DefaultAgentPort {
+		return fmt.Errorf("metrics.port must not be %d, it is used by the backup agent", n.DefaultAgentPort)
+	}
+	return nil
+}
+
 func ValidateHotBackupSpec(hb *hazelcastv1alpha1.HotBackup) error {
 	if hb.Spec.Secret == "" {
 		return errors.New("when using external Backup, Secret must be set")
@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+)
+
+func Test_validateJVM(t *testing.T) {
+	quantity := func(s string) *resource.Quantity {
+		q := resource.MustParse(s)
+		return &q
+	}
+	tests := []struct {
+		name        string
+		initialHeap *resource.Quantity
+		maxHeap     *resource.Quantity
+		memoryLimit string
+		wantErr     bool
+	}{
+		{name: "Heap derived from memory limit", memoryLimit: "1Gi"},
+		{name: "Initial heap below derived heap", initialHeap: quantity("512Mi"), memoryLimit: "1Gi"},
+		{name: "Initial heap above derived heap", initialHeap: quantity("900Mi"), memoryLimit: "1Gi", wantErr: true},
+		{name: "Initial heap above max heap", initialHeap: quantity("512Mi"), maxHeap: quantity("256Mi"), wantErr: true},
+		{name: "Max heap not below memory limit", maxHeap: quantity("1Gi"), memoryLimit: "1Gi", wantErr: true},
+		{name: "Initial heap below 1Mi", initialHeap: quantity("512Ki"), wantErr: true},
+		{name: "Max heap below 1Mi", maxHeap: quantity("512Ki"), wantErr: true},
+		{name: "Memory limit too low for derived heap", memoryLimit: "1Mi", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hazelcastv1alpha1.Hazelcast{
+				Spec: hazelcastv1alpha1.HazelcastSpec{
+					JVM: &hazelcastv1alpha1.JVMConfiguration{
+						Memory: &hazelcastv1alpha1.JVMMemoryConfiguration{
+							InitialHeap: tt.initialHeap,
+							MaxHeap:     tt.maxHeap,
+						},
+					},
+				},
+			}
+			if tt.memoryLimit != "" {
+				h.Spec.Resources = &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(tt.memoryLimit)},
+				}
+			}
+			if err := validateJVM(h); (err != nil) != tt.wantErr {
+				t.Errorf("validateJVM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -202,6 +204,41 @@ func ports() []v1.ServicePort {
 	}
 }
 
+func (r *ManagementCenterReconciler) reconcilePodDisruptionBudget(ctx context.Context, mc *hazelcastv1alpha1.ManagementCenter, logger logr.Logger) error {
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metadata(mc),
+	}
+
+	if !mc.Spec.PodDisruptionBudget.IsEnabled() {
+		err := r.Client.Delete(ctx, pdb)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PodDisruptionBudget: %w", err)
+		}
+		return nil
+	}
+
+	err := controllerutil.SetControllerReference(mc, pdb, r.Scheme)
+	if err != nil {
+		return fmt.Errorf("failed to set owner reference on PodDisruptionBudget: %w", err)
+	}
+
+	opResult, err := util.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+		maxUnavailable := intstr.FromInt(1)
+		if mc.Spec.PodDisruptionBudget.MaxUnavailable != nil {
+			maxUnavailable = *mc.Spec.PodDisruptionBudget.MaxUnavailable
+		}
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+		pdb.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: labels(mc),
+		}
+		return nil
+	})
+	if opResult != controllerutil.OperationResultNone {
+		logger.Info("Operation result", "PodDisruptionBudget", mc.Name, "result", opResult)
+	}
+	return err
+}
+
 func (r *ManagementCenterReconciler) reconcileStatefulset(ctx context.Context, mc *hazelcastv1alpha1.ManagementCenter, logger logr.Logger) error {
 	ls := labels(mc)
 	sts := &appsv1.StatefulSet{
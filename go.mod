@@ -8,6 +8,7 @@ require (
 	github.com/hazelcast/hazelcast-go-client v1.2.0
 	github.com/onsi/ginkgo/v2 v2.1.3
 	github.com/onsi/gomega v1.18.1
+	github.com/prometheus/client_golang v1.7.1
 	github.com/robfig/cron/v3 v3.0.0
 	golang.org/x/tools v0.1.7 // indirect
 	google.golang.org/api v0.20.0
@@ -5,11 +5,20 @@ type HazelcastWrapper struct {
 }
 
 type Hazelcast struct {
-	Jet         Jet            `yaml:"jet,omitempty"`
-	Network     Network        `yaml:"network,omitempty"`
-	ClusterName string         `yaml:"cluster-name,omitempty"`
-	Persistence Persistence    `yaml:"persistence,omitempty"`
-	Map         map[string]Map `yaml:"map,omitempty"`
+	Import         []string          `yaml:"import,omitempty"`
+	Jet            Jet               `yaml:"jet,omitempty"`
+	Network        Network           `yaml:"network,omitempty"`
+	ClusterName    string            `yaml:"cluster-name,omitempty"`
+	Persistence    Persistence       `yaml:"persistence,omitempty"`
+	Map            map[string]Map    `yaml:"map,omitempty"`
+	Security       Security          `yaml:"security,omitempty"`
+	Properties     map[string]string `yaml:"properties,omitempty"`
+	PartitionGroup PartitionGroup    `yaml:"partition-group,omitempty"`
+}
+
+type PartitionGroup struct {
+	Enabled   *bool  `yaml:"enabled,omitempty"`
+	GroupType string `yaml:"group-type,omitempty"`
 }
 
 type Jet struct {
@@ -19,6 +28,54 @@ type Jet struct {
 type Network struct {
 	Join    Join    `yaml:"join,omitempty"`
 	RestAPI RestAPI `yaml:"rest-api,omitempty"`
+	SSL     SSL     `yaml:"ssl,omitempty"`
+}
+
+type SSL struct {
+	Enabled          *bool             `yaml:"enabled,omitempty"`
+	FactoryClassName string            `yaml:"factory-class-name,omitempty"`
+	Properties       map[string]string `yaml:"properties,omitempty"`
+}
+
+type Security struct {
+	Enabled              *bool                `yaml:"enabled,omitempty"`
+	Realms               []Realm              `yaml:"realms,omitempty"`
+	ClientAuthentication ClientAuthentication `yaml:"client-authentication,omitempty"`
+	ClientPermissions    ClientPermissions    `yaml:"client-permissions,omitempty"`
+}
+
+type Realm struct {
+	Name           string         `yaml:"name"`
+	Authentication Authentication `yaml:"authentication"`
+}
+
+type Authentication struct {
+	Simple SimpleAuthentication `yaml:"simple"`
+}
+
+type SimpleAuthentication struct {
+	Users []User `yaml:"users"`
+}
+
+type User struct {
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	Roles    []string `yaml:"roles,omitempty"`
+}
+
+type ClientAuthentication struct {
+	Realm string `yaml:"realm,omitempty"`
+}
+
+type ClientPermissions struct {
+	All *Permission  `yaml:"all,omitempty"`
+	Map []Permission `yaml:"map,omitempty"`
+}
+
+type Permission struct {
+	Name      string   `yaml:"name,omitempty"`
+	Principal string   `yaml:"principal,omitempty"`
+	Actions   []string `yaml:"actions,omitempty"`
 }
 
 type Join struct {
@@ -61,16 +118,89 @@ type EndpointGroup struct {
 }
 
 type Map struct {
-	BackupCount       int32         `yaml:"backup-count"`
-	AsyncBackupCount  int32         `yaml:"async-backup-count"`
-	TimeToLiveSeconds int32         `yaml:"time-to-live-seconds"`
-	MaxIdleSeconds    int32         `yaml:"max-idle-seconds"`
-	Eviction          MapEviction   `yaml:"eviction,omitempty"`
-	ReadBackupData    bool          `yaml:"read-backup-data"`
-	InMemoryFormat    string        `yaml:"in-memory-format"`
-	StatisticsEnabled bool          `yaml:"statistics-enabled"`
-	Indexes           []MapIndex    `yaml:"indexes,omitempty"`
-	HotRestart        MapHotRestart `yaml:"hot-restart,omitempty"`
+	BackupCount       int32                        `yaml:"backup-count"`
+	AsyncBackupCount  int32                        `yaml:"async-backup-count"`
+	TimeToLiveSeconds int32                        `yaml:"time-to-live-seconds"`
+	MaxIdleSeconds    int32                        `yaml:"max-idle-seconds"`
+	Eviction          MapEviction                  `yaml:"eviction,omitempty"`
+	ReadBackupData    bool                         `yaml:"read-backup-data"`
+	InMemoryFormat    string                       `yaml:"in-memory-format"`
+	StatisticsEnabled bool                         `yaml:"statistics-enabled"`
+	Indexes           []MapIndex                   `yaml:"indexes,omitempty"`
+	HotRestart        MapHotRestart                `yaml:"hot-restart,omitempty"`
+	NearCache         *NearCache                   `yaml:"near-cache,omitempty"`
+	MapStore          *MapStore                    `yaml:"map-store,omitempty"`
+	EntryListeners    []EntryListener              `yaml:"entry-listeners,omitempty"`
+	EventJournal      *EventJournal                `yaml:"event-journal,omitempty"`
+	MerkleTree        *MerkleTree                  `yaml:"merkle-tree,omitempty"`
+	WanReplicationRef map[string]WanReplicationRef `yaml:"wan-replication-ref,omitempty"`
+	QueryCaches       map[string]QueryCache        `yaml:"query-caches,omitempty"`
+}
+
+type QueryCache struct {
+	Predicate      QueryCachePredicate `yaml:"predicate"`
+	BatchSize      int32               `yaml:"batch-size"`
+	BufferSize     int32               `yaml:"buffer-size"`
+	Coalesce       bool                `yaml:"coalesce"`
+	Populate       bool                `yaml:"populate"`
+	IncludeValue   bool                `yaml:"include-value"`
+	InMemoryFormat string              `yaml:"in-memory-format"`
+	Eviction       MapEviction         `yaml:"eviction"`
+	Indexes        []MapIndex          `yaml:"indexes,omitempty"`
+}
+
+type QueryCachePredicate struct {
+	SQL       string `yaml:"sql,omitempty"`
+	ClassName string `yaml:"class-name,omitempty"`
+}
+
+type MerkleTree struct {
+	Enabled bool  `yaml:"enabled"`
+	Depth   int32 `yaml:"depth"`
+}
+
+type WanReplicationRef struct {
+	MergePolicyClassName string   `yaml:"merge-policy-class-name"`
+	RepublishingEnabled  bool     `yaml:"republishing-enabled"`
+	Filters              []string `yaml:"filters,omitempty"`
+}
+
+type EventJournal struct {
+	Enabled           bool  `yaml:"enabled"`
+	Capacity          int32 `yaml:"capacity"`
+	TimeToLiveSeconds int32 `yaml:"time-to-live-seconds"`
+}
+
+type EntryListener struct {
+	ClassName    string `yaml:"class-name"`
+	IncludeValue bool   `yaml:"include-value"`
+	Local        bool   `yaml:"local"`
+}
+
+type MapStore struct {
+	Enabled           bool   `yaml:"enabled"`
+	ClassName         string `yaml:"class-name"`
+	WriteDelaySeconds int32  `yaml:"write-delay-seconds"`
+	WriteBatchSize    int32  `yaml:"write-batch-size"`
+	WriteCoalescing   bool   `yaml:"write-coalescing"`
+	InitialLoadMode   string `yaml:"initial-mode"`
+}
+
+type NearCache struct {
+	InMemoryFormat     string             `yaml:"in-memory-format"`
+	InvalidateOnChange bool               `yaml:"invalidate-on-change"`
+	TimeToLiveSeconds  int32              `yaml:"time-to-live-seconds"`
+	MaxIdleSeconds     int32              `yaml:"max-idle-seconds"`
+	CacheLocalEntries  bool               `yaml:"cache-local-entries"`
+	Eviction           MapEviction        `yaml:"eviction"`
+	Preloader          NearCachePreloader `yaml:"preloader,omitempty"`
+}
+
+type NearCachePreloader struct {
+	Enabled                  bool   `yaml:"enabled"`
+	Directory                string `yaml:"directory,omitempty"`
+	StoreInitialDelaySeconds int32  `yaml:"store-initial-delay-seconds,omitempty"`
+	StoreIntervalSeconds     int32  `yaml:"store-interval-seconds,omitempty"`
 }
 
 type MapEviction struct {
@@ -107,6 +237,72 @@ func (hz Hazelcast) HazelcastConfigForcingRestart() Hazelcast {
 					UseNodeNameAsExternalAddress: hz.Network.Join.Kubernetes.UseNodeNameAsExternalAddress,
 				},
 			},
+			SSL: hz.Network.SSL,
 		},
+		Security:       hz.Security,
+		Properties:     propertiesForcingRestart(hz.Properties),
+		PartitionGroup: hz.PartitionGroup,
+	}
+}
+
+// restartRequiredProperties are the properties whose changes take effect only after the members are restarted.
+var restartRequiredProperties = map[string]struct{}{
+	"hazelcast.partition.count":                    {},
+	"hazelcast.heartbeat.interval.seconds":         {},
+	"hazelcast.max.no.heartbeat.seconds":           {},
+	"hazelcast.heartbeat.failuredetector.type":     {},
+	"hazelcast.merge.first.run.delay.seconds":      {},
+	"hazelcast.merge.next.run.delay.seconds":       {},
+	"hazelcast.initial.min.cluster.size":           {},
+	"hazelcast.initial.wait.seconds":               {},
+	"hazelcast.io.thread.count":                    {},
+	"hazelcast.operation.thread.count":             {},
+	"hazelcast.operation.generic.thread.count":     {},
+	"hazelcast.partition.operation.thread.count":   {},
+	"hazelcast.logging.type":                       {},
+	"hazelcast.shutdownhook.enabled":               {},
+	"hazelcast.shutdownhook.policy":                {},
+	"hazelcast.graceful.shutdown.max.wait":         {},
+	"hazelcast.jmx":                                {},
+	"hazelcast.socket.bind.any":                    {},
+	"hazelcast.socket.receive.buffer.size":         {},
+	"hazelcast.socket.send.buffer.size":            {},
+	"hazelcast.partition.migration.chunks.enabled": {},
+}
+
+func propertiesForcingRestart(p map[string]string) map[string]string {
+	var res map[string]string
+	for k, v := range p {
+		if _, ok := restartRequiredProperties[k]; !ok {
+			continue
+		}
+		if res == nil {
+			res = map[string]string{}
+		}
+		res[k] = v
 	}
+	return res
+}
+
+// SecretsWrapper is the part of the Hazelcast configuration with the values read from Secrets.
+// It is imported by the configuration in the ConfigMap, so that the values are not stored in the ConfigMap.
+type SecretsWrapper struct {
+	Hazelcast Secrets `yaml:"hazelcast"`
+}
+
+type Secrets struct {
+	Security *SecretSecurity      `yaml:"security,omitempty"`
+	Map      map[string]SecretMap `yaml:"map,omitempty"`
+}
+
+type SecretSecurity struct {
+	Realms []Realm `yaml:"realms"`
+}
+
+type SecretMap struct {
+	MapStore SecretMapStore `yaml:"map-store"`
+}
+
+type SecretMapStore struct {
+	Properties map[string]string `yaml:"properties"`
 }
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func Test_HazelcastConfigForcingRestartProperties(t *testing.T) {
+	hz := Hazelcast{
+		Properties: map[string]string{
+			"hazelcast.partition.count":                 "7",
+			"hazelcast.client.max.no.heartbeat.seconds": "30",
+		},
+	}
+	want := map[string]string{"hazelcast.partition.count": "7"}
+	if got := hz.HazelcastConfigForcingRestart().Properties; !reflect.DeepEqual(got, want) {
+		t.Errorf("HazelcastConfigForcingRestart().Properties = %v, want %v", got, want)
+	}
+}
+
+func Test_ClientPermissionsYAML(t *testing.T) {
+	sec := Security{
+		ClientPermissions: ClientPermissions{
+			All: &Permission{Principal: "operator"},
+			Map: []Permission{{Name: "orders", Principal: "app", Actions: []string{"read"}}},
+		},
+	}
+	out, err := yaml.Marshal(sec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// The all permission is a single mapping, not a sequence
+	if !strings.Contains(string(out), "all:\n        principal: operator\n") {
+		t.Errorf("Unexpected YAML for the all permission:\n%s", out)
+	}
+
+	got := Security{}
+	if err := yaml.Unmarshal(out, &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, sec) {
+		t.Errorf("Round trip = %+v, want %+v", got, sec)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Merge deep-merges the custom configuration into the generated one. Keys present in both
+// are resolved in favour of the generated configuration, since those are owned by the operator.
+// It returns the merged configuration and the sorted paths of the custom keys that were overridden.
+func Merge(generated, custom map[string]interface{}) (map[string]interface{}, []string) {
+	var conflicts []string
+	merged := mergeMaps(generated, custom, nil, &conflicts)
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
+
+func mergeMaps(generated, custom map[string]interface{}, path []string, conflicts *[]string) map[string]interface{} {
+	merged := make(map[string]interface{}, len(generated)+len(custom))
+	for k, v := range custom {
+		merged[k] = v
+	}
+	for k, gv := range generated {
+		cv, ok := custom[k]
+		if !ok {
+			merged[k] = gv
+			continue
+		}
+		p := append(append([]string{}, path...), k)
+		gm, gIsMap := asMap(gv)
+		cm, cIsMap := asMap(cv)
+		switch {
+		case gIsMap && cIsMap:
+			merged[k] = mergeMaps(gm, cm, p, conflicts)
+		default:
+			if !reflect.DeepEqual(gv, cv) {
+				*conflicts = append(*conflicts, strings.Join(p, "."))
+			}
+			merged[k] = gv
+		}
+	}
+	return merged
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			res[fmt.Sprint(k)] = v
+		}
+		return res, true
+	default:
+		return nil, false
+	}
+}
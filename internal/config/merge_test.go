@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Merge(t *testing.T) {
+	tests := []struct {
+		name          string
+		generated     map[string]interface{}
+		custom        map[string]interface{}
+		want          map[string]interface{}
+		wantConflicts []string
+	}{
+		{
+			name:      "Custom keys are added",
+			generated: map[string]interface{}{"cluster-name": "dev"},
+			custom:    map[string]interface{}{"properties": map[string]interface{}{"hazelcast.logging.type": "log4j2"}},
+			want: map[string]interface{}{
+				"cluster-name": "dev",
+				"properties":   map[string]interface{}{"hazelcast.logging.type": "log4j2"},
+			},
+		},
+		{
+			name: "Nested maps are merged",
+			generated: map[string]interface{}{
+				"network": map[string]interface{}{"rest-api": map[string]interface{}{"enabled": true}},
+			},
+			custom: map[string]interface{}{
+				"network": map[string]interface{}{"port": map[string]interface{}{"auto-increment": false}},
+			},
+			want: map[string]interface{}{
+				"network": map[string]interface{}{
+					"rest-api": map[string]interface{}{"enabled": true},
+					"port":     map[string]interface{}{"auto-increment": false},
+				},
+			},
+		},
+		{
+			name: "Generated values win and differing ones are reported",
+			generated: map[string]interface{}{
+				"cluster-name": "dev",
+				"network":      map[string]interface{}{"rest-api": map[string]interface{}{"enabled": true}},
+			},
+			custom: map[string]interface{}{
+				"cluster-name": "prod",
+				"network":      map[string]interface{}{"rest-api": map[string]interface{}{"enabled": false}},
+			},
+			want: map[string]interface{}{
+				"cluster-name": "dev",
+				"network":      map[string]interface{}{"rest-api": map[string]interface{}{"enabled": true}},
+			},
+			wantConflicts: []string{"cluster-name", "network.rest-api.enabled"},
+		},
+		{
+			name:      "Equal values are not reported",
+			generated: map[string]interface{}{"cluster-name": "dev"},
+			custom:    map[string]interface{}{"cluster-name": "dev"},
+			want:      map[string]interface{}{"cluster-name": "dev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge(tt.generated, tt.custom)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(conflicts, tt.wantConflicts) {
+				t.Errorf("Merge() conflicts = %v, want %v", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Package metrics contains the Prometheus metrics of the operator.
+// They are registered on the controller-runtime registry and served on the metrics endpoint of the manager.
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+)
+
+const namespace = "hazelcast_operator"
+
+var phases = []hazelcastv1alpha1.Phase{
+	hazelcastv1alpha1.Running,
+	hazelcastv1alpha1.Failed,
+	hazelcastv1alpha1.Pending,
+	hazelcastv1alpha1.Scaling,
+	hazelcastv1alpha1.Upgrading,
+}
+
+var (
+	clusterPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_phase",
+		Help:      "Phase of the Hazelcast cluster, 1 for the current phase and 0 for the others.",
+	}, []string{"namespace", "name", "phase"})
+
+	clusterReadyMembers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_ready_members",
+		Help:      "Number of Hazelcast members connected to the cluster.",
+	}, []string{"namespace", "name"})
+
+	clusterDesiredMembers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_desired_members",
+		Help:      "Number of Hazelcast members requested in the cluster spec.",
+	}, []string{"namespace", "name"})
+
+	memberOwnedPartitions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "member_owned_partitions",
+		Help:      "Number of partitions owned by the Hazelcast member.",
+	}, []string{"namespace", "name", "member"})
+
+	hotBackups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hot_backups_total",
+		Help:      "Number of Hot Backups triggered on the Hazelcast cluster.",
+	}, []string{"namespace", "name"})
+
+	hotBackupFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hot_backup_failures_total",
+		Help:      "Number of failed Hot Backups of the Hazelcast cluster.",
+	}, []string{"namespace", "name"})
+
+	mapConfigFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "map_config_failures_total",
+		Help:      "Number of failures applying the Map configuration on a Hazelcast member.",
+	}, []string{"namespace", "name", "member"})
+
+	forceStarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "force_starts_total",
+		Help:      "Number of Force Start actions triggered on the Hazelcast cluster.",
+	}, []string{"namespace", "name"})
+)
+
+var (
+	// clusterMembers keeps the members with a member_owned_partitions series for each cluster,
+	// so that the series can be removed once the members leave or the cluster is deleted.
+	clusterMembers   = map[types.NamespacedName]map[string]struct{}{}
+	clusterMembersMu sync.Mutex
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		clusterPhase,
+		clusterReadyMembers,
+		clusterDesiredMembers,
+		memberOwnedPartitions,
+		hotBackups,
+		hotBackupFailures,
+		mapConfigFailures,
+		forceStarts,
+	)
+}
+
+// SetClusterStatus records the phase and the number of members of the Hazelcast cluster.
+func SetClusterStatus(h *hazelcastv1alpha1.Hazelcast) {
+	for _, p := range phases {
+		v := 0.0
+		if p == h.Status.Phase {
+			v = 1
+		}
+		clusterPhase.WithLabelValues(h.Namespace, h.Name, string(p)).Set(v)
+	}
+	clusterReadyMembers.WithLabelValues(h.Namespace, h.Name).Set(float64(h.Status.ClusterSize))
+	if h.Spec.ClusterSize != nil {
+		clusterDesiredMembers.WithLabelValues(h.Namespace, h.Name).Set(float64(*h.Spec.ClusterSize))
+	}
+}
+
+// DeleteClusterStatus removes the metrics of the deleted Hazelcast cluster.
+func DeleteClusterStatus(h *hazelcastv1alpha1.Hazelcast) {
+	for _, p := range phases {
+		clusterPhase.DeleteLabelValues(h.Namespace, h.Name, string(p))
+	}
+	clusterReadyMembers.DeleteLabelValues(h.Namespace, h.Name)
+	clusterDesiredMembers.DeleteLabelValues(h.Namespace, h.Name)
+	forceStarts.DeleteLabelValues(h.Namespace, h.Name)
+	hotBackups.DeleteLabelValues(h.Namespace, h.Name)
+	hotBackupFailures.DeleteLabelValues(h.Namespace, h.Name)
+	SetMemberPartitions(types.NamespacedName{Name: h.Name, Namespace: h.Namespace}, nil)
+}
+
+// SetMemberPartitions records the number of partitions owned by each member, the members not in the map are removed.
+func SetMemberPartitions(nn types.NamespacedName, partitions map[string]int32) {
+	clusterMembersMu.Lock()
+	defer clusterMembersMu.Unlock()
+	for m := range clusterMembers[nn] {
+		if _, ok := partitions[m]; !ok {
+			memberOwnedPartitions.DeleteLabelValues(nn.Namespace, nn.Name, m)
+		}
+	}
+	if len(partitions) == 0 {
+		delete(clusterMembers, nn)
+		return
+	}
+	members := make(map[string]struct{}, len(partitions))
+	for m, p := range partitions {
+		memberOwnedPartitions.WithLabelValues(nn.Namespace, nn.Name, m).Set(float64(p))
+		members[m] = struct{}{}
+	}
+	clusterMembers[nn] = members
+}
+
+// IncHotBackups counts a Hot Backup triggered on the cluster of the HotBackup resource.
+func IncHotBackups(hb *hazelcastv1alpha1.HotBackup) {
+	hotBackups.WithLabelValues(hb.Namespace, hb.Spec.HazelcastResourceName).Inc()
+}
+
+// IncHotBackupFailures counts a failed Hot Backup of the cluster of the HotBackup resource.
+func IncHotBackupFailures(hb *hazelcastv1alpha1.HotBackup) {
+	hotBackupFailures.WithLabelValues(hb.Namespace, hb.Spec.HazelcastResourceName).Inc()
+}
+
+// IncMapConfigFailures counts a failure applying the Map configuration on the given member.
+func IncMapConfigFailures(m *hazelcastv1alpha1.Map, member string) {
+	mapConfigFailures.WithLabelValues(m.Namespace, m.Name, member).Inc()
+}
+
+// DeleteMapConfigFailures removes the failure counts of the Map for the given members,
+// either because they left the cluster or because the Map is deleted.
+func DeleteMapConfigFailures(m *hazelcastv1alpha1.Map, members []string) {
+	for _, member := range members {
+		mapConfigFailures.DeleteLabelValues(m.Namespace, m.Name, member)
+	}
+}
+
+// IncForceStarts counts a Force Start action triggered on the Hazelcast cluster.
+func IncForceStarts(h *hazelcastv1alpha1.Hazelcast) {
+	forceStarts.WithLabelValues(h.Namespace, h.Name).Inc()
+}
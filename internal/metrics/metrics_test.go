@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
+)
+
+func TestSetClusterStatus(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{Name: "hazelcast", Namespace: "default"},
+		Spec:       hazelcastv1alpha1.HazelcastSpec{ClusterSize: &[]int32{3}[0]},
+		Status:     hazelcastv1alpha1.HazelcastStatus{Phase: hazelcastv1alpha1.Pending, ClusterSize: 2},
+	}
+	SetClusterStatus(h)
+
+	if v := testutil.ToFloat64(clusterPhase.WithLabelValues("default", "hazelcast", "Pending")); v != 1 {
+		t.Errorf("Expected Pending phase to be 1, got %v", v)
+	}
+	if v := testutil.ToFloat64(clusterPhase.WithLabelValues("default", "hazelcast", "Running")); v != 0 {
+		t.Errorf("Expected Running phase to be 0, got %v", v)
+	}
+	if v := testutil.ToFloat64(clusterReadyMembers.WithLabelValues("default", "hazelcast")); v != 2 {
+		t.Errorf("Expected 2 ready members, got %v", v)
+	}
+
+	DeleteClusterStatus(h)
+	if c := testutil.CollectAndCount(clusterPhase); c != 0 {
+		t.Errorf("Expected phase metrics to be removed, got %d", c)
+	}
+}
+
+func TestSetMemberPartitionsRemovesLeftMembers(t *testing.T) {
+	nn := types.NamespacedName{Name: "hazelcast", Namespace: "default"}
+	SetMemberPartitions(nn, map[string]int32{"a": 135, "b": 136})
+	SetMemberPartitions(nn, map[string]int32{"a": 271})
+
+	if c := testutil.CollectAndCount(memberOwnedPartitions); c != 1 {
+		t.Errorf("Expected a single member, got %d", c)
+	}
+	if v := testutil.ToFloat64(memberOwnedPartitions.WithLabelValues("default", "hazelcast", "a")); v != 271 {
+		t.Errorf("Expected 271 partitions, got %v", v)
+	}
+}
+
+func TestDeleteClusterStatusRemovesMemberAndHotBackupMetrics(t *testing.T) {
+	h := &hazelcastv1alpha1.Hazelcast{
+		ObjectMeta: metav1.ObjectMeta{Name: "hazelcast-deleted", Namespace: "default"},
+	}
+	hb := &hazelcastv1alpha1.HotBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "hot-backup", Namespace: "default"},
+		Spec:       hazelcastv1alpha1.HotBackupSpec{HazelcastResourceName: h.Name},
+	}
+	members := testutil.CollectAndCount(memberOwnedPartitions)
+	SetMemberPartitions(types.NamespacedName{Name: h.Name, Namespace: h.Namespace}, map[string]int32{"a": 271})
+	IncHotBackups(hb)
+	IncHotBackupFailures(hb)
+
+	DeleteClusterStatus(h)
+	if c := testutil.CollectAndCount(memberOwnedPartitions); c != members {
+		t.Errorf("Expected the member partitions to be removed, got %d series", c)
+	}
+	if c := testutil.CollectAndCount(hotBackups); c != 0 {
+		t.Errorf("Expected the Hot Backup counts to be removed, got %d", c)
+	}
+	if c := testutil.CollectAndCount(hotBackupFailures); c != 0 {
+		t.Errorf("Expected the Hot Backup failure counts to be removed, got %d", c)
+	}
+}
+
+func TestDeleteMapConfigFailures(t *testing.T) {
+	m := &hazelcastv1alpha1.Map{ObjectMeta: metav1.ObjectMeta{Name: "map", Namespace: "default"}}
+	IncMapConfigFailures(m, "a")
+	IncMapConfigFailures(m, "b")
+
+	DeleteMapConfigFailures(m, []string{"a"})
+	if c := testutil.CollectAndCount(mapConfigFailures); c != 1 {
+		t.Errorf("Expected the failures of a single member, got %d", c)
+	}
+	DeleteMapConfigFailures(m, []string{"b"})
+	if c := testutil.CollectAndCount(mapConfigFailures); c != 0 {
+		t.Errorf("Expected the failures to be removed, got %d", c)
+	}
+}
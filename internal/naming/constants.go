@@ -17,7 +17,11 @@ const (
 	ServicePerPodCountAnnotation                 = "hazelcast.com/service-per-pod-count"
 	ExposeExternallyAnnotation                   = "hazelcast.com/expose-externally-member-access"
 	LastSuccessfulSpecAnnotation                 = "hazelcast.com/last-successful-spec"
+	MapDestroyedAnnotation                       = "hazelcast.com/map-destroyed"
 	CurrentHazelcastConfigForcingRestartChecksum = "hazelcast.com/current-hazelcast-config-forcing-restart-checksum"
+	CustomConfigChecksum                         = "hazelcast.com/custom-config-checksum"
+	SecurityChecksum                             = "hazelcast.com/security-checksum"
+	TLSChecksum                                  = "hazelcast.com/tls-checksum"
 
 	// PodNameLabel label that represents the name of the pod in the StatefulSet
 	PodNameLabel = "statefulset.kubernetes.io/pod-name"
@@ -36,6 +40,7 @@ const (
 	HazelcastPortName    = "hazelcast-port"
 	HazelcastStorageName = Hazelcast + "-storage"
 	HazelcastMountPath   = "/data/hazelcast"
+	HazelcastConfigKey   = "hazelcast.yaml"
 
 	// ManagementCenter MC name
 	ManagementCenter = "management-center"
@@ -49,6 +54,7 @@ const (
 
 	BackupAgent         = "backup-agent"
 	BackupAgentPortName = "backup-agent-port"
+	MetricsPortName     = "metrics"
 	RestoreAgent        = "restore-agent"
 	BucketSecret        = "br-secret"
 
@@ -69,6 +75,23 @@ const (
 	BucketDataAzureEnvStorageAccount = "AZURE_STORAGE_ACCOUNT"
 	BucketDataAzureEnvStorageKey     = "AZURE_STORAGE_KEY"
 
+	TLSVolumeName = "tls"
+	TLSMountPath  = "/data/tls"
+	TLSCAKey      = "ca.crt"
+
+	SecurityUsernameKey    = "username"
+	SecurityPasswordKey    = "password"
+	SecurityTokenKey       = "token"
+	SecurityRealm          = "client-realm"
+	OperatorIdentityName   = "operator"
+	OperatorIdentitySuffix = "-operator-identity"
+
+	// SecretConfigSuffix is the suffix of the Secret with the part of the Hazelcast configuration read from Secrets
+	SecretConfigSuffix       = "-secret-config"
+	SecretConfigVolumeName   = "secret-config"
+	SecretConfigMountPath    = "/data/secret-config"
+	HazelcastSecretConfigKey = "hazelcast-secrets.yaml"
+
 	GCP   = "gs"
 	AWS   = "s3"
 	AZURE = "azblob"
@@ -78,6 +101,8 @@ const (
 const (
 	// DefaultHzPort Hazelcast default port
 	DefaultHzPort = 5701
+	// DefaultMetricsPort Prometheus metrics exporter default port
+	DefaultMetricsPort = 8081
 	// DefaultClusterSize default number of members of Hazelcast cluster
 	DefaultClusterSize = 3
 	// DefaultClusterName default name of Hazelcast cluster
@@ -111,6 +136,31 @@ const (
 	DefaultMapEvictionPolicy     = "NONE"
 	DefaultMapMaxSizePolicy      = "PER_NODE"
 	DefaultMapMaxSize            = int32(0)
+	DefaultMapAsyncBackupCount   = int32(0)
+	DefaultMapReadBackupData     = false
+	DefaultMapInMemoryFormat     = "BINARY"
+	DefaultMapStatisticsEnabled  = true
+	MaxMapBackupCount            = int32(6)
+)
+
+// Near Cache Config default values
+const (
+	DefaultNearCacheName                     = "default"
+	DefaultNearCacheEvictionPolicy           = "LRU"
+	DefaultNearCacheMaxSizePolicy            = "ENTRY_COUNT"
+	DefaultNearCacheSize                     = int32(10000)
+	DefaultNearCacheLocalUpdatePolicy        = "INVALIDATE"
+	DefaultNearCachePreloaderDelaySeconds    = int32(600)
+	DefaultNearCachePreloaderIntervalSeconds = int32(600)
+)
+
+// Query Cache Config default values
+const (
+	DefaultQueryCacheBatchSize      = int32(1)
+	DefaultQueryCacheBufferSize     = int32(16)
+	DefaultQueryCacheEvictionPolicy = "LRU"
+	DefaultQueryCacheMaxSizePolicy  = "ENTRY_COUNT"
+	DefaultQueryCacheSize           = int32(10000)
 )
 
 // Operator Values
@@ -7,6 +7,7 @@ import (
 
 	iserialization "github.com/hazelcast/hazelcast-go-client"
 	proto "github.com/hazelcast/hazelcast-go-client"
+	"github.com/hazelcast/hazelcast-go-client/types"
 )
 
 // Encoder for ClientMessage and value
@@ -243,3 +244,23 @@ func DecodeListMultiFrame(frameIterator *proto.ForwardFrameIterator, decoder fun
 	}
 	frameIterator.Next()
 }
+
+func EncodeUUID(buffer []byte, offset int32, uuid types.UUID) {
+	isNull := uuid.Default()
+	EncodeBoolean(buffer, offset, isNull)
+	if isNull {
+		return
+	}
+	EncodeLong(buffer, offset+proto.BooleanSizeInBytes, int64(uuid.MostSignificantBits()))
+	EncodeLong(buffer, offset+proto.BooleanSizeInBytes+proto.LongSizeInBytes, int64(uuid.LeastSignificantBits()))
+}
+
+func DecodeListLong(frameIterator *proto.ForwardFrameIterator) []int64 {
+	frame := frameIterator.Next()
+	itemCount := len(frame.Content) / proto.LongSizeInBytes
+	result := make([]int64, itemCount)
+	for i := 0; i < itemCount; i++ {
+		result[i] = DecodeLong(frame.Content, int32(i*proto.LongSizeInBytes))
+	}
+	return result
+}
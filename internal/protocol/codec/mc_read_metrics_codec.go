@@ -0,0 +1,76 @@
+/*
+* Copyright (c) 2008-2022, Hazelcast, Inc. All Rights Reserved.
+*
+* Licensed under the Apache License, Version 2.0 (the "License")
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+ */
+
+package codec
+
+import (
+	proto "github.com/hazelcast/hazelcast-go-client"
+	hztypes "github.com/hazelcast/hazelcast-go-client/types"
+
+	"github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
+)
+
+const (
+	MCReadMetricsCodecRequestMessageType  = int32(0x200100)
+	MCReadMetricsCodecResponseMessageType = int32(0x200101)
+
+	MCReadMetricsCodecRequestUuidOffset         = proto.PartitionIDOffset + proto.IntSizeInBytes
+	MCReadMetricsCodecRequestFromSequenceOffset = MCReadMetricsCodecRequestUuidOffset + proto.UUIDSizeInBytes
+	MCReadMetricsCodecRequestInitialFrameSize   = MCReadMetricsCodecRequestFromSequenceOffset + proto.LongSizeInBytes
+
+	MCReadMetricsResponseNextSequenceOffset = proto.ResponseBackupAcksOffset + proto.ByteSizeInBytes
+)
+
+// Reads the metrics collected by the member with the given UUID since the given sequence.
+
+func EncodeMCReadMetricsRequest(uuid hztypes.UUID, fromSequence int64) *proto.ClientMessage {
+	clientMessage := proto.NewClientMessageForEncode()
+	clientMessage.SetRetryable(true)
+
+	initialFrame := proto.NewFrameWith(make([]byte, MCReadMetricsCodecRequestInitialFrameSize), proto.UnfragmentedMessage)
+	EncodeUUID(initialFrame.Content, MCReadMetricsCodecRequestUuidOffset, uuid)
+	EncodeLong(initialFrame.Content, MCReadMetricsCodecRequestFromSequenceOffset, fromSequence)
+	clientMessage.AddFrame(initialFrame)
+	clientMessage.SetMessageType(MCReadMetricsCodecRequestMessageType)
+	clientMessage.SetPartitionId(-1)
+
+	return clientMessage
+}
+
+func DecodeMCReadMetricsResponse(clientMessage *proto.ClientMessage) (elements []types.MetricsBlob, nextSequence int64) {
+	frameIterator := clientMessage.FrameIterator()
+	initialFrame := frameIterator.Next()
+
+	nextSequence = DecodeLong(initialFrame.Content, MCReadMetricsResponseNextSequenceOffset)
+	elements = DecodeEntryListForLongAndByteArray(frameIterator)
+	return elements, nextSequence
+}
+
+func DecodeEntryListForLongAndByteArray(frameIterator *proto.ForwardFrameIterator) []types.MetricsBlob {
+	var blobs [][]byte
+	frameIterator.Next()
+	for !NextFrameIsDataStructureEndFrame(frameIterator) {
+		blobs = append(blobs, frameIterator.Next().Content)
+	}
+	frameIterator.Next()
+
+	keys := DecodeListLong(frameIterator)
+	result := make([]types.MetricsBlob, len(blobs))
+	for i := range blobs {
+		result[i] = types.MetricsBlob{Timestamp: keys[i], Blob: blobs[i]}
+	}
+	return result
+}
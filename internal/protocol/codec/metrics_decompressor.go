@@ -0,0 +1,223 @@
+package codec
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"unicode/utf16"
+
+	"github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
+)
+
+// The format of the blob is defined by com.hazelcast.internal.metrics.impl.MetricsCompressor.
+const (
+	metricsBinaryFormatVersion = 1
+
+	metricsMaskPrefix             byte = 1 << 0
+	metricsMaskMetric             byte = 1 << 1
+	metricsMaskDiscriminator      byte = 1 << 2
+	metricsMaskDiscriminatorValue byte = 1 << 3
+	metricsMaskUnit               byte = 1 << 4
+	metricsMaskExcludedTargets    byte = 1 << 5
+	metricsMaskTagCount           byte = 1 << 6
+
+	metricsNullDictionaryID = -1
+
+	metricsValueTypeLong   = 0
+	metricsValueTypeDouble = 1
+)
+
+// DecodeMetricsBlob decompresses the metrics of a blob read with the MC.readMetrics request.
+func DecodeMetricsBlob(blob []byte) ([]types.Metric, error) {
+	r := bytes.NewReader(blob)
+	var version int16
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != metricsBinaryFormatVersion {
+		return nil, fmt.Errorf("unsupported metrics format version %d", version)
+	}
+
+	var dictSize int32
+	if err := binary.Read(r, binary.BigEndian, &dictSize); err != nil {
+		return nil, err
+	}
+	dictBlob := make([]byte, dictSize)
+	if _, err := io.ReadFull(r, dictBlob); err != nil {
+		return nil, err
+	}
+	dict, err := decodeMetricsDictionary(dictBlob)
+	if err != nil {
+		return nil, fmt.Errorf("metrics dictionary: %w", err)
+	}
+
+	var count int32
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	metricsBlob, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err := inflate(metricsBlob)
+	if err != nil {
+		return nil, fmt.Errorf("metrics: %w", err)
+	}
+	return decodeMetrics(bytes.NewReader(data), dict, int(count))
+}
+
+func inflate(b []byte) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return ioutil.ReadAll(zr)
+}
+
+// decodeMetricsDictionary returns the words of the dictionary by their ID.
+// The words are sorted and each one is written as the length of the prefix it shares with the previous word
+// followed by the remaining characters.
+func decodeMetricsDictionary(blob []byte) (map[int32]string, error) {
+	data, err := inflate(blob)
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(data)
+	var count int32
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	dict := make(map[int32]string, count)
+	var last []uint16
+	for i := int32(0); i < count; i++ {
+		var header struct {
+			ID        int32
+			CommonLen uint8
+			DiffLen   uint8
+		}
+		if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+			return nil, err
+		}
+		if int(header.CommonLen) > len(last) {
+			return nil, errors.New("invalid common prefix length")
+		}
+		diff := make([]uint16, header.DiffLen)
+		if err := binary.Read(r, binary.BigEndian, diff); err != nil {
+			return nil, err
+		}
+		word := append(append([]uint16{}, last[:header.CommonLen]...), diff...)
+		dict[header.ID] = string(utf16.Decode(word))
+		last = word
+	}
+	return dict, nil
+}
+
+func decodeMetrics(r *bytes.Reader, dict map[int32]string, count int) ([]types.Metric, error) {
+	word := func() (string, error) {
+		var id int32
+		if err := binary.Read(r, binary.BigEndian, &id); err != nil {
+			return "", err
+		}
+		if id == metricsNullDictionaryID {
+			return "", nil
+		}
+		w, ok := dict[id]
+		if !ok {
+			return "", fmt.Errorf("unknown dictionary id %d", id)
+		}
+		return w, nil
+	}
+
+	metrics := make([]types.Metric, 0, count)
+	// Each metric contains only the parts of its descriptor which differ from the previous metric
+	var last types.Metric
+	var tagCount byte
+	for i := 0; i < count; i++ {
+		mask, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		m := types.Metric{
+			Prefix:             last.Prefix,
+			Name:               last.Name,
+			Discriminator:      last.Discriminator,
+			DiscriminatorValue: last.DiscriminatorValue,
+		}
+		if mask&metricsMaskPrefix == 0 {
+			if m.Prefix, err = word(); err != nil {
+				return nil, err
+			}
+		}
+		if mask&metricsMaskMetric == 0 {
+			if m.Name, err = word(); err != nil {
+				return nil, err
+			}
+		}
+		if mask&metricsMaskDiscriminator == 0 {
+			if m.Discriminator, err = word(); err != nil {
+				return nil, err
+			}
+		}
+		if mask&metricsMaskDiscriminatorValue == 0 {
+			if m.DiscriminatorValue, err = word(); err != nil {
+				return nil, err
+			}
+		}
+		if mask&metricsMaskUnit == 0 {
+			if _, err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		if mask&metricsMaskExcludedTargets == 0 {
+			if _, err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		if mask&metricsMaskTagCount == 0 {
+			if tagCount, err = r.ReadByte(); err != nil {
+				return nil, err
+			}
+		}
+		for t := byte(0); t < tagCount; t++ {
+			tag, err := word()
+			if err != nil {
+				return nil, err
+			}
+			value, err := word()
+			if err != nil {
+				return nil, err
+			}
+			if m.Tags == nil {
+				m.Tags = map[string]string{}
+			}
+			m.Tags[tag] = value
+		}
+
+		valueType, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		var bits uint64
+		if err := binary.Read(r, binary.BigEndian, &bits); err != nil {
+			return nil, err
+		}
+		switch valueType {
+		case metricsValueTypeLong:
+			m.Value = int64(bits)
+		case metricsValueTypeDouble:
+			m.Value = int64(math.Float64frombits(bits))
+		default:
+			return nil, fmt.Errorf("unknown metric value type %d", valueType)
+		}
+
+		metrics = append(metrics, m)
+		last = m
+	}
+	return metrics, nil
+}
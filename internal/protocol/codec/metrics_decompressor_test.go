@@ -0,0 +1,71 @@
+package codec
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/hazelcast/hazelcast-platform-operator/internal/protocol/types"
+)
+
+func deflate(t *testing.T, b []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	w.Close()
+	return buf.Bytes()
+}
+
+func write(buf *bytes.Buffer, values ...interface{}) {
+	for _, v := range values {
+		_ = binary.Write(buf, binary.BigEndian, v)
+	}
+}
+
+func Test_DecodeMetricsBlob(t *testing.T) {
+	// Words sorted as written by the member: 0 map, 1 name, 2 orders, 3 ownedEntryCount, 4 ownedEntryMemoryCost
+	dict := &bytes.Buffer{}
+	write(dict, int32(5))
+	for _, w := range []struct {
+		id     int32
+		common uint8
+		diff   string
+	}{{0, 0, "map"}, {1, 0, "name"}, {2, 0, "orders"}, {3, 1, "wnedEntryCount"}, {4, 10, "MemoryCost"}} {
+		write(dict, w.id, w.common, uint8(len(w.diff)))
+		for _, c := range w.diff {
+			write(dict, uint16(c))
+		}
+	}
+
+	metrics := &bytes.Buffer{}
+	// Full descriptor without tags
+	write(metrics, byte(0), int32(0), int32(3), int32(1), int32(2), byte(4), byte(0), byte(0))
+	write(metrics, byte(metricsValueTypeLong), int64(42))
+	// Only the metric differs from the previous one, with a double value
+	write(metrics, byte(0x7f&^metricsMaskMetric), int32(4))
+	write(metrics, byte(metricsValueTypeDouble), math.Float64bits(1024.5))
+
+	compressedDict := deflate(t, dict.Bytes())
+	blob := &bytes.Buffer{}
+	write(blob, int16(1), int32(len(compressedDict)))
+	blob.Write(compressedDict)
+	write(blob, int32(2))
+	blob.Write(deflate(t, metrics.Bytes()))
+
+	got, err := DecodeMetricsBlob(blob.Bytes())
+	if err != nil {
+		t.Fatalf("DecodeMetricsBlob() error = %v", err)
+	}
+	want := []types.Metric{
+		{Prefix: "map", Name: "ownedEntryCount", Discriminator: "name", DiscriminatorValue: "orders", Value: 42},
+		{Prefix: "map", Name: "ownedEntryMemoryCost", Discriminator: "name", DiscriminatorValue: "orders", Value: 1024},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeMetricsBlob() = %+v, want %+v", got, want)
+	}
+}
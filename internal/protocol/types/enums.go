@@ -16,6 +16,17 @@ const (
 	UniqueKeyTransformationRaw    UniqueKeyTransformation = 2
 )
 
+type ListenerConfigType int32
+
+const (
+	ListenerConfigTypeGeneric              ListenerConfigType = 0
+	ListenerConfigTypeItem                 ListenerConfigType = 1
+	ListenerConfigTypeEntry                ListenerConfigType = 2
+	ListenerConfigTypeSplitBrainProtection ListenerConfigType = 3
+	ListenerConfigTypeCachePartitionLost   ListenerConfigType = 4
+	ListenerConfigTypeMapPartitionLost     ListenerConfigType = 5
+)
+
 // +kubebuilder:validation:Enum=PER_NODE;PER_PARTITION;USED_HEAP_SIZE;USED_HEAP_PERCENTAGE;FREE_HEAP_SIZE;FREE_HEAP_PERCENTAGE;USED_NATIVE_MEMORY_SIZE;USED_NATIVE_MEMORY_PERCENTAGE;FREE_NATIVE_MEMORY_SIZE;FREE_NATIVE_MEMORY_PERCENTAGE
 type MaxSizePolicyType string
 
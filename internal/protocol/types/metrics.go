@@ -0,0 +1,18 @@
+package types
+
+// MetricsBlob is the compressed metrics collected by a member at the given time.
+type MetricsBlob struct {
+	Timestamp int64
+	Blob      []byte
+}
+
+// Metric is a single metric decompressed from a MetricsBlob.
+type Metric struct {
+	Prefix             string
+	Name               string
+	Discriminator      string
+	DiscriminatorValue string
+	Tags               map[string]string
+	// Value of the metric, the double values are truncated
+	Value int64
+}
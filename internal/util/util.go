@@ -11,6 +11,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
@@ -244,18 +245,6 @@ func getLoadBalancerAddress(lb *corev1.LoadBalancerIngress) string {
 	return ""
 }
 
-func IndexConfigSliceEquals(a, b []hazelcastv1alpha1.IndexConfig) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i, v := range a {
-		if !indexConfigEquals(v, b[i]) {
-			return false
-		}
-	}
-	return true
-}
-
 func indexConfigEquals(a, b hazelcastv1alpha1.IndexConfig) bool {
 	if a.Name != b.Name {
 		return false
@@ -269,9 +258,59 @@ func indexConfigEquals(a, b hazelcastv1alpha1.IndexConfig) bool {
 		return false
 	}
 
-	if a.BitmapIndexOptions != b.BitmapIndexOptions {
+	if (a.BitmapIndexOptions == nil) != (b.BitmapIndexOptions == nil) {
+		return false
+	}
+	if a.BitmapIndexOptions != nil && *a.BitmapIndexOptions != *b.BitmapIndexOptions {
+		return false
+	}
+	return true
+}
+
+// IndexKey identifies the index config by its name, or by its type and attributes if it has no name.
+func IndexKey(ic hazelcastv1alpha1.IndexConfig) string {
+	if ic.Name != "" {
+		return ic.Name
+	}
+	return fmt.Sprintf("%s[%s]", ic.Type, strings.Join(ic.Attributes, ","))
+}
+
+// AddedIndexConfigs returns the index configs of current that are not in last.
+// It returns an error if an index config of last is removed or changed in current, since indexes cannot be dropped at runtime.
+func AddedIndexConfigs(current, last []hazelcastv1alpha1.IndexConfig) ([]hazelcastv1alpha1.IndexConfig, error) {
+	byKey := make(map[string]hazelcastv1alpha1.IndexConfig, len(current))
+	for _, ic := range current {
+		byKey[IndexKey(ic)] = ic
+	}
+	for _, ic := range last {
+		key := IndexKey(ic)
+		c, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("index %s cannot be removed", key)
+		}
+		if !indexConfigEquals(c, ic) {
+			return nil, fmt.Errorf("index %s cannot be changed, only new indexes can be added", key)
+		}
+		delete(byKey, key)
+	}
+	added := make([]hazelcastv1alpha1.IndexConfig, 0, len(byKey))
+	for _, ic := range current {
+		if _, ok := byKey[IndexKey(ic)]; ok {
+			added = append(added, ic)
+		}
+	}
+	return added, nil
+}
+
+func EntryListenerConfigSliceEquals(a, b []hazelcastv1alpha1.EntryListenerConfig) bool {
+	if len(a) != len(b) {
 		return false
 	}
+	for i, v := range a {
+		if v.ClassName != b[i].ClassName || v.IsIncludeValue() != b[i].IsIncludeValue() || v.Local != b[i].Local {
+			return false
+		}
+	}
 	return true
 }
 
@@ -286,3 +325,23 @@ func stringSliceEquals(a, b []string) bool {
 	}
 	return true
 }
+
+// heapPercentageOfMemoryLimit is the part of the container memory limit used for the heap when it is not set explicitly.
+// The rest is left for the metaspace, thread stacks and the other native memory of the JVM.
+const heapPercentageOfMemoryLimit = 80
+
+// MaxHeap returns the maximum heap of the Hazelcast members, either set explicitly or derived from the memory limit
+// of the container. It returns nil if neither is set.
+func MaxHeap(h *hazelcastv1alpha1.Hazelcast) *resource.Quantity {
+	if h.Spec.JVM != nil && h.Spec.JVM.Memory != nil && h.Spec.JVM.Memory.MaxHeap != nil {
+		return h.Spec.JVM.Memory.MaxHeap
+	}
+	if h.Spec.Resources == nil {
+		return nil
+	}
+	limit, ok := h.Spec.Resources.Limits[corev1.ResourceMemory]
+	if !ok || limit.IsZero() {
+		return nil
+	}
+	return resource.NewQuantity(limit.Value()*heapPercentageOfMemoryLimit/100, resource.BinarySI)
+}
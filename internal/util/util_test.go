@@ -1,10 +1,13 @@
 package util
 
 import (
+	"reflect"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hazelcastv1alpha1 "github.com/hazelcast/hazelcast-platform-operator/api/v1alpha1"
 )
 
 func Test_isStatefulSetReady(t *testing.T) {
@@ -78,3 +81,83 @@ func Test_deploymentName(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseVersion(t *testing.T) {
+	tests := []struct {
+		tag     string
+		version Version
+		ok      bool
+	}{
+		{tag: "5.1.2", version: Version{Major: 5, Minor: 1, Patch: 2}, ok: true},
+		{tag: "5.2", version: Version{Major: 5, Minor: 2}, ok: true},
+		{tag: "5.1.2-slim", version: Version{Major: 5, Minor: 1, Patch: 2}, ok: true},
+		{tag: "latest", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, ok := ParseVersion(tt.tag)
+			if ok != tt.ok || got != tt.version {
+				t.Errorf("ParseVersion() = %v, %v, want %v, %v", got, ok, tt.version, tt.ok)
+			}
+		})
+	}
+}
+
+func Test_AddedIndexConfigs(t *testing.T) {
+	byName := hazelcastv1alpha1.IndexConfig{Name: "name-idx", Type: hazelcastv1alpha1.IndexTypeHash, Attributes: []string{"name"}}
+	unnamed := hazelcastv1alpha1.IndexConfig{Type: hazelcastv1alpha1.IndexTypeSorted, Attributes: []string{"age"}}
+	bitmap := hazelcastv1alpha1.IndexConfig{
+		Name:               "bitmap-idx",
+		Type:               hazelcastv1alpha1.IndexTypeBitmap,
+		Attributes:         []string{"status"},
+		BitmapIndexOptions: &hazelcastv1alpha1.BitmapIndexOptionsConfig{UniqueKey: "id", UniqueKeyTransition: hazelcastv1alpha1.UniqueKeyTransitionLong},
+	}
+	bitmapCopy := bitmap
+	bitmapCopy.BitmapIndexOptions = &hazelcastv1alpha1.BitmapIndexOptionsConfig{UniqueKey: "id", UniqueKeyTransition: hazelcastv1alpha1.UniqueKeyTransitionLong}
+	changed := byName
+	changed.Attributes = []string{"surname"}
+
+	tests := []struct {
+		name    string
+		current []hazelcastv1alpha1.IndexConfig
+		last    []hazelcastv1alpha1.IndexConfig
+		want    []hazelcastv1alpha1.IndexConfig
+		wantErr bool
+	}{
+		{
+			name:    "No change",
+			current: []hazelcastv1alpha1.IndexConfig{byName, bitmapCopy},
+			last:    []hazelcastv1alpha1.IndexConfig{bitmap, byName},
+			want:    []hazelcastv1alpha1.IndexConfig{},
+		},
+		{
+			name:    "Indexes added",
+			current: []hazelcastv1alpha1.IndexConfig{byName, unnamed, bitmap},
+			last:    []hazelcastv1alpha1.IndexConfig{byName},
+			want:    []hazelcastv1alpha1.IndexConfig{unnamed, bitmap},
+		},
+		{
+			name:    "Index removed",
+			current: []hazelcastv1alpha1.IndexConfig{unnamed},
+			last:    []hazelcastv1alpha1.IndexConfig{byName, unnamed},
+			wantErr: true,
+		},
+		{
+			name:    "Index changed",
+			current: []hazelcastv1alpha1.IndexConfig{changed},
+			last:    []hazelcastv1alpha1.IndexConfig{byName},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AddedIndexConfigs(tt.current, tt.last)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddedIndexConfigs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AddedIndexConfigs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var versionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// Version is the numeric part of a Hazelcast image tag, e.g. 5.1.2 for "5.1.2-slim".
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses the given image tag. It returns false if the tag does not start with a version, e.g. "latest".
+func ParseVersion(s string) (Version, bool) {
+	m := versionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, false
+	}
+	v := Version{}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, true
+}
+
+// Compare returns -1, 0 or 1 if v is lower, equal or greater than o respectively.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// ClusterVersion returns the cluster version of the members running v, e.g. "5.1".
+func (v Version) ClusterVersion() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
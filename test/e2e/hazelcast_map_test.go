@@ -214,4 +214,32 @@ var _ = Describe("Hazelcast Map Config", Label("map"), func() {
 		Expect(k8sClient.Update(context.Background(), m)).Should(Succeed())
 		assertMapStatus(m, hazelcastcomv1alpha1.MapFailed)
 	})
+
+	It("should add an index to the existing map and fail to remove it", Label("fast"), func() {
+		hazelcast := hazelcastconfig.Default(hzLookupKey, ee, labels)
+		CreateHazelcastCR(hazelcast)
+
+		By("creating the map config successfully")
+		m := hazelcastconfig.DefaultMap(mapLookupKey, hazelcast.Name, labels)
+		Expect(k8sClient.Create(context.Background(), m)).Should(Succeed())
+		m = assertMapStatus(m, hazelcastcomv1alpha1.MapSuccess)
+
+		By("adding an index to the map")
+		m.Spec.Indexes = []hazelcastcomv1alpha1.IndexConfig{
+			{
+				Name:       "index-1",
+				Type:       hazelcastcomv1alpha1.IndexTypeSorted,
+				Attributes: []string{"attribute1"},
+			},
+		}
+		Expect(k8sClient.Update(context.Background(), m)).Should(Succeed())
+		m = assertMapStatus(m, hazelcastcomv1alpha1.MapSuccess)
+		Expect(m.Status.AddedIndexes).Should(ConsistOf("index-1"))
+
+		By("failing to remove the index")
+		m.Spec.Indexes = nil
+		Expect(k8sClient.Update(context.Background(), m)).Should(Succeed())
+		m = assertMapStatus(m, hazelcastcomv1alpha1.MapFailed)
+		Expect(m.Status.Message).Should(Equal("index index-1 cannot be removed"))
+	})
 })
@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -171,6 +172,15 @@ var _ = Describe("Hazelcast controller", func() {
 		return hz
 	}
 
+	EnsureScalingStatus := func(hz *hazelcastv1alpha1.Hazelcast) *hazelcastv1alpha1.Hazelcast {
+		By("ensuring that the status is scaling")
+		Eventually(func() hazelcastv1alpha1.Phase {
+			hz = Fetch(hz)
+			return hz.Status.Phase
+		}, timeout, interval).Should(Equal(hazelcastv1alpha1.Scaling))
+		return hz
+	}
+
 	EnsureFailedStatus := func(hz *hazelcastv1alpha1.Hazelcast) *hazelcastv1alpha1.Hazelcast {
 		By("ensuring that the status is failed")
 		Eventually(func() hazelcastv1alpha1.Phase {
@@ -233,6 +243,32 @@ var _ = Describe("Hazelcast controller", func() {
 			Expect(fetchedSts.Spec.Template.Spec.Containers[0].Image).Should(Equal(fetchedCR.DockerImage()))
 			Expect(fetchedSts.Spec.Template.Spec.Containers[0].ImagePullPolicy).Should(Equal(fetchedCR.Spec.ImagePullPolicy))
 
+			By("creating the PodDisruptionBudget derived from the map backup count")
+			fetchedPdb := &policyv1beta1.PodDisruptionBudget{}
+			assertExists(lookupKey(hz), fetchedPdb)
+			Expect(fetchedPdb.ObjectMeta.OwnerReferences).To(ContainElement(expectedOwnerReference))
+			Expect(fetchedPdb.Spec.MaxUnavailable.IntValue()).Should(Equal(1))
+
+			By("exposing the pod selector for the scale subresource")
+			selector, err := metav1.LabelSelectorAsSelector(fetchedSts.Spec.Selector)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fetchedCR.Status.Selector).Should(Equal(selector.String()))
+
+			By("reporting the conditions of the cluster")
+			Eventually(func() []metav1.Condition {
+				return Fetch(hz).Status.Conditions
+			}, timeout, interval).Should(And(
+				ContainElement(And(
+					HaveField("Type", hazelcastv1alpha1.ConfigApplied),
+					HaveField("Status", metav1.ConditionTrue),
+					HaveField("ObservedGeneration", fetchedCR.Generation),
+				)),
+				ContainElement(And(
+					HaveField("Type", hazelcastv1alpha1.StatefulSetReady),
+					HaveField("Status", metav1.ConditionFalse),
+				)),
+			))
+
 			Delete(hz)
 
 			By("Expecting to ClusterRole and ClusterRoleBinding removed via finalizer")
@@ -242,6 +278,43 @@ var _ = Describe("Hazelcast controller", func() {
 		})
 	})
 
+	Context("Hazelcast CustomResource in Running phase", func() {
+		It("should keep the conditions set during the reconcile", Label("fast"), func() {
+			hz := &hazelcastv1alpha1.Hazelcast{
+				ObjectMeta: GetRandomObjectMeta(),
+				Spec:       test.HazelcastSpec(defaultSpecValues, ee),
+			}
+
+			Create(hz)
+			EnsureStatus(hz)
+
+			By("marking the StatefulSet ready")
+			sts := &v1.StatefulSet{}
+			assertExists(lookupKey(hz), sts)
+			sts.Status.ObservedGeneration = sts.Generation
+			sts.Status.Replicas = *sts.Spec.Replicas
+			sts.Status.ReadyReplicas = *sts.Spec.Replicas
+			sts.Status.UpdatedReplicas = *sts.Spec.Replicas
+			Expect(k8sClient.Status().Update(context.Background(), sts)).Should(Succeed())
+
+			Eventually(func() hazelcastv1alpha1.Phase {
+				return Fetch(hz).Status.Phase
+			}, timeout, interval).Should(Equal(hazelcastv1alpha1.Running))
+
+			fetchedCR := Fetch(hz)
+			Expect(fetchedCR.ObjectMeta.Annotations).To(HaveKey(n.LastSuccessfulSpecAnnotation))
+			Expect(fetchedCR.Status.Conditions).To(And(
+				ContainElement(And(
+					HaveField("Type", hazelcastv1alpha1.StatefulSetReady),
+					HaveField("Status", metav1.ConditionTrue),
+				)),
+				ContainElement(HaveField("Type", hazelcastv1alpha1.ClusterSafe)),
+			))
+
+			Delete(hz)
+		})
+	})
+
 	Context("Hazelcast CustomResource with expose externally", func() {
 		FetchServices := func(hz *hazelcastv1alpha1.Hazelcast, waitForN int) *corev1.ServiceList {
 			serviceList := &corev1.ServiceList{}
@@ -344,9 +417,12 @@ var _ = Describe("Hazelcast controller", func() {
 			fetchedCR = EnsureStatus(hz)
 			Update(fetchedCR, SetClusterSize(1))
 			fetchedCR = Fetch(fetchedCR)
-			EnsureStatus(fetchedCR)
+			EnsureScalingStatus(fetchedCR)
 			FetchServices(fetchedCR, 2)
 
+			By("checking that the members are not removed until the cluster is safe")
+			Expect(*getStatefulSet(fetchedCR).Spec.Replicas).Should(Equal(int32(6)))
+
 			By("deleting the cluster")
 			Delete(fetchedCR)
 		})
@@ -498,6 +574,44 @@ var _ = Describe("Hazelcast controller", func() {
 	})
 
 	Context("Pod scheduling parameters", func() {
+		When("HighAvailabilityMode is used", func() {
+			It("should spread the members across zones", Label("fast"), func() {
+				spec := test.HazelcastSpec(defaultSpecValues, ee)
+				spec.HighAvailabilityMode = hazelcastv1alpha1.HighAvailabilityModeZone
+				hz := &hazelcastv1alpha1.Hazelcast{
+					ObjectMeta: GetRandomObjectMeta(),
+					Spec:       spec,
+				}
+				Create(hz)
+
+				Eventually(func() []corev1.TopologySpreadConstraint {
+					ss := getStatefulSet(hz)
+					return ss.Spec.Template.Spec.TopologySpreadConstraints
+				}, timeout, interval).Should(ConsistOf(WithTransform(func(c corev1.TopologySpreadConstraint) string {
+					return c.TopologyKey
+				}, Equal(corev1.LabelTopologyZone))))
+
+				Delete(hz)
+			})
+
+			It("should fail when all members are pinned to the same zone", Label("fast"), func() {
+				spec := test.HazelcastSpec(defaultSpecValues, ee)
+				spec.HighAvailabilityMode = hazelcastv1alpha1.HighAvailabilityModeZone
+				spec.Scheduling = &hazelcastv1alpha1.SchedulingConfiguration{
+					NodeSelector: map[string]string{
+						corev1.LabelTopologyZone: "us-west-1a",
+					},
+				}
+				hz := &hazelcastv1alpha1.Hazelcast{
+					ObjectMeta: GetRandomObjectMeta(),
+					Spec:       spec,
+				}
+				Create(hz)
+				EnsureFailedStatus(hz)
+				Delete(hz)
+			})
+		})
+
 		When("NodeSelector is used", func() {
 			It("should pass the values to StatefulSet spec", Label("fast"), func() {
 				spec := test.HazelcastSpec(defaultSpecValues, ee)
@@ -689,6 +803,18 @@ var _ = Describe("Hazelcast controller", func() {
 
 			})
 		})
+		When("Using an unknown in-memory format", func() {
+			It("should fail to create Map CR", Label("fast"), func() {
+				m := &hazelcastv1alpha1.Map{
+					ObjectMeta: GetRandomObjectMeta(),
+					Spec: hazelcastv1alpha1.MapSpec{
+						HazelcastResourceName: "hazelcast",
+						InMemoryFormat:        "CUSTOM",
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), m)).ShouldNot(Succeed())
+			})
+		})
 		When("Using default configuration", func() {
 			It("should create Map CR with default configurations", Label("fast"), func() {
 				m := &hazelcastv1alpha1.Map{
@@ -704,6 +830,10 @@ var _ = Describe("Hazelcast controller", func() {
 				By("checking the CR values with default ones")
 				Expect(ms.Name).To(Equal(""))
 				Expect(*ms.BackupCount).To(Equal(n.DefaultMapBackupCount))
+				Expect(ms.AsyncBackupCount).To(Equal(n.DefaultMapAsyncBackupCount))
+				Expect(ms.ReadBackupData).To(Equal(n.DefaultMapReadBackupData))
+				Expect(ms.InMemoryFormat).To(Equal(hazelcastv1alpha1.InMemoryFormatType(n.DefaultMapInMemoryFormat)))
+				Expect(*ms.StatisticsEnabled).To(Equal(n.DefaultMapStatisticsEnabled))
 				Expect(*ms.TimeToLiveSeconds).To(Equal(n.DefaultMapTimeToLiveSeconds))
 				Expect(*ms.MaxIdleSeconds).To(Equal(n.DefaultMapMaxIdleSeconds))
 				Expect(ms.Eviction.EvictionPolicy).To(Equal(hazelcastv1alpha1.EvictionPolicyType(n.DefaultMapEvictionPolicy)))
@@ -711,6 +841,7 @@ var _ = Describe("Hazelcast controller", func() {
 				Expect(ms.Eviction.MaxSizePolicy).To(Equal(hazelcastv1alpha1.MaxSizePolicyType(n.DefaultMapMaxSizePolicy)))
 				Expect(ms.Indexes).To(BeNil())
 				Expect(ms.PersistenceEnabled).To(Equal(n.DefaultMapPersistenceEnabled))
+				Expect(ms.DeletionPolicy).To(Equal(hazelcastv1alpha1.DeletionPolicyRetain))
 				Expect(ms.HazelcastResourceName).To(Equal("hazelcast"))
 			})
 		})
@@ -791,6 +922,85 @@ var _ = Describe("Hazelcast controller", func() {
 		})
 	})
 
+	Context("TLS configuration", func() {
+		When("TLS is configured", func() {
+			It("should mount the TLS secret and use HTTPS probes", Label("fast"), func() {
+				if !ee {
+					Skip("This test will only run in EE configuration")
+				}
+				spec := test.HazelcastSpec(defaultSpecValues, ee)
+				spec.TLS = &hazelcastv1alpha1.TLSConfiguration{
+					SecretName: "tls-secret",
+				}
+				hz := &hazelcastv1alpha1.Hazelcast{
+					ObjectMeta: GetRandomObjectMeta(),
+					Spec:       spec,
+				}
+
+				Create(hz)
+				EnsureStatus(hz)
+
+				ss := getStatefulSet(hz)
+				Expect(ss.Spec.Template.Spec.Volumes).Should(ContainElement(corev1.Volume{
+					Name: n.TLSVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName:  "tls-secret",
+							DefaultMode: pointer.Int32Ptr(420),
+						},
+					},
+				}))
+				Expect(ss.Spec.Template.Spec.Containers[0].VolumeMounts).Should(ContainElement(corev1.VolumeMount{
+					Name:      n.TLSVolumeName,
+					MountPath: n.TLSMountPath,
+					ReadOnly:  true,
+				}))
+				Expect(ss.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet.Scheme).Should(Equal(corev1.URISchemeHTTPS))
+				Expect(ss.Spec.Template.Spec.Containers[0].ReadinessProbe.HTTPGet.Scheme).Should(Equal(corev1.URISchemeHTTPS))
+
+				Delete(hz)
+			})
+		})
+	})
+
+	Context("Metrics configuration", func() {
+		When("metrics are enabled", func() {
+			It("should expose the metrics port and enable the exporter", Label("fast"), func() {
+				spec := test.HazelcastSpec(defaultSpecValues, ee)
+				spec.Metrics = &hazelcastv1alpha1.MetricsConfiguration{
+					Port: 9090,
+				}
+				hz := &hazelcastv1alpha1.Hazelcast{
+					ObjectMeta: GetRandomObjectMeta(),
+					Spec:       spec,
+				}
+
+				Create(hz)
+				EnsureStatus(hz)
+
+				ss := getStatefulSet(hz)
+				Expect(ss.Spec.Template.Spec.Containers[0].Ports).Should(ContainElement(corev1.ContainerPort{
+					Name:          n.MetricsPortName,
+					ContainerPort: 9090,
+					Protocol:      corev1.ProtocolTCP,
+				}))
+				Expect(ss.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(And(
+					HaveField("Name", "JAVA_OPTS"),
+					HaveField("Value", ContainSubstring("jmx_prometheus_javaagent.jar=9090:")),
+				)))
+
+				svc := &corev1.Service{}
+				assertExists(lookupKey(hz), svc)
+				Expect(svc.Spec.Ports).Should(ContainElement(And(
+					HaveField("Name", n.MetricsPortName),
+					HaveField("Port", int32(9090)),
+				)))
+
+				Delete(hz)
+			})
+		})
+	})
+
 	Context("Statefulset Updates", func() {
 		firstSpec := hazelcastv1alpha1.HazelcastSpec{
 			ClusterSize:      pointer.Int32Ptr(2),
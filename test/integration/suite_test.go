@@ -69,6 +69,7 @@ var _ = BeforeSuite(func() {
 		k8sManager.GetClient(),
 		ctrl.Log.WithName("controllers").WithName("Hazelcast"),
 		k8sManager.GetScheme(),
+		k8sManager.GetEventRecorderFor("hazelcast-controller"),
 		nil,
 	).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())